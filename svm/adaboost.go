@@ -0,0 +1,95 @@
+package svm
+
+import "math"
+
+// adaBoostMinError keeps the alpha of a weak learner finite when it makes no weighted errors.
+const adaBoostMinError = 1e-10
+
+// An AdaBoostClassifier classifies samples by a weighted vote of linear classifiers, as trained
+// by TrainAdaBoost.
+type AdaBoostClassifier struct {
+	Classifiers []*LinearClassifier
+
+	// Alphas contains the weight of each classifier's vote.
+	Alphas []float64
+}
+
+// TrainAdaBoost boosts linear classifiers using AdaBoost for the given number of rounds.
+//
+// Each round trains a weak learner with the SubgradientSolver, using per-sample weights which
+// emphasize the samples misclassified by the previous learners.
+// The learner's alpha is computed from its weighted error, and the weights are updated
+// accordingly.
+// Training stops early if a learner does no better than chance on the weighted samples.
+//
+// If the Problem has its own sample weights, they are used as the initial weights.
+// The weights are always normalized to average 1, so the solver's Tradeoff has the same meaning
+// as it does for an unweighted Problem.
+func TrainAdaBoost(p *Problem, s *SubgradientSolver, rounds int) *AdaBoostClassifier {
+	count := p.sampleCount()
+	weights := make([]float64, count)
+	for i := range weights {
+		weights[i] = p.sampleWeight(i)
+	}
+
+	res := &AdaBoostClassifier{}
+	for round := 0; round < rounds; round++ {
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+		for i := range weights {
+			weights[i] *= float64(count) / total
+		}
+
+		weighted := *p
+		weighted.PositiveWeights = weights[:len(p.Positives)]
+		weighted.NegativeWeights = weights[len(p.Positives):]
+		learner := s.Solve(&weighted)
+
+		var weightedError float64
+		for i, w := range weights {
+			sample, sign := p.sample(i)
+			if learner.Classify(sample) != (sign > 0) {
+				weightedError += w / float64(count)
+			}
+		}
+		if weightedError >= 0.5 {
+			break
+		}
+		weightedError = math.Max(weightedError, adaBoostMinError)
+		alpha := math.Log((1-weightedError)/weightedError) / 2
+
+		res.Classifiers = append(res.Classifiers, learner)
+		res.Alphas = append(res.Alphas, alpha)
+
+		for i := range weights {
+			sample, sign := p.sample(i)
+			weights[i] *= math.Exp(-alpha * sign * voteSign(learner, sample))
+		}
+	}
+	return res
+}
+
+// Classify returns true if the alpha-weighted vote is positive.
+func (a *AdaBoostClassifier) Classify(sample Sample) bool {
+	return positiveDecision(a.Rating(sample))
+}
+
+// Rating returns the alpha-weighted sum of the votes, where a vote is 1 for a positive
+// classification and -1 for a negative one.
+func (a *AdaBoostClassifier) Rating(sample Sample) float64 {
+	var sum float64
+	for i, classifier := range a.Classifiers {
+		sum += a.Alphas[i] * voteSign(classifier, sample)
+	}
+	return sum
+}
+
+// voteSign returns 1 if a classifier classifies a sample as positive, or -1 otherwise.
+func voteSign(c Classifier, sample Sample) float64 {
+	if c.Classify(sample) {
+		return 1
+	}
+	return -1
+}
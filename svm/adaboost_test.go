@@ -0,0 +1,50 @@
+package svm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTrainAdaBoost(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := cornerProblem(rng, 200)
+	solver := &SubgradientSolver{Tradeoff: 0.01, Steps: 100, StepSize: 0.01}
+	boosted := TrainAdaBoost(problem, solver, 30)
+	if len(boosted.Classifiers) < 10 {
+		t.Fatal("boosting stopped after", len(boosted.Classifiers), "rounds")
+	}
+
+	var errors []float64
+	for rounds := 1; rounds <= len(boosted.Classifiers); rounds++ {
+		partial := &AdaBoostClassifier{
+			Classifiers: boosted.Classifiers[:rounds],
+			Alphas:      boosted.Alphas[:rounds],
+		}
+		errors = append(errors, 1-problemAccuracy(partial, problem))
+	}
+
+	longSolver := *solver
+	longSolver.Steps *= len(boosted.Classifiers)
+	single := 1 - problemAccuracy(longSolver.Solve(problem), problem)
+	if single < 0.05 {
+		t.Fatal("single linear model should not fit the corner:", single)
+	}
+	if final := errors[len(errors)-1]; final > errors[0] || final > single/2 {
+		t.Errorf("boosted error %f should be well below single error %f", final, single)
+	}
+}
+
+// cornerProblem generates a Problem whose positives lie in the upper right quadrant of the square
+// [-1, 1]^2, which no linear classifier can separate from the negatives.
+func cornerProblem(rng *rand.Rand, count int) *Problem {
+	res := &Problem{Kernel: LinearKernel}
+	for i := 0; i < count; i++ {
+		sample := Sample{V: []float64{rng.Float64()*2 - 1, rng.Float64()*2 - 1}}
+		if sample.V[0] > 0 && sample.V[1] > 0 {
+			res.Positives = append(res.Positives, sample)
+		} else {
+			res.Negatives = append(res.Negatives, sample)
+		}
+	}
+	return res
+}
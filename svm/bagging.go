@@ -0,0 +1,70 @@
+package svm
+
+import "math/rand"
+
+// A BaggingClassifier classifies samples by the majority vote of an ensemble of classifiers, each
+// of which was trained on a bootstrap resample of the same Problem.
+//
+// Averaging over many resamples reduces the variance of the base classifiers, which helps most
+// when the training data are noisy.
+type BaggingClassifier struct {
+	Classifiers []*LinearClassifier
+}
+
+// TrainBagging trains a BaggingClassifier with n base classifiers, using a Solver to train each
+// of them.
+//
+// Each base classifier is trained on a bootstrap resample of the Problem, which is generated by
+// drawing samples from the Problem with replacement using rng.
+// The positives and negatives are resampled separately, so every resample has the same number
+// of positives and negatives as the original Problem.
+// Sample weights are carried over to the resampled Problems.
+func TrainBagging(p *Problem, s Solver, n int, rng *rand.Rand) *BaggingClassifier {
+	res := &BaggingClassifier{Classifiers: make([]*LinearClassifier, n)}
+	for i := range res.Classifiers {
+		res.Classifiers[i] = s.Solve(bootstrapResample(p, rng))
+	}
+	return res
+}
+
+// Classify returns true if more than half of the base classifiers classify the sample as
+// positive.
+// A tied vote classifies the sample as negative.
+func (b *BaggingClassifier) Classify(sample Sample) bool {
+	positive, negative := b.Votes(sample)
+	return positive > negative
+}
+
+// Rating returns the difference between the fraction of base classifiers which classify the
+// sample as positive and the fraction which classify it as negative.
+func (b *BaggingClassifier) Rating(sample Sample) float64 {
+	positive, negative := b.Votes(sample)
+	return float64(positive-negative) / float64(len(b.Classifiers))
+}
+
+// Votes returns the number of base classifiers which classify the sample as positive and the
+// number which classify it as negative.
+func (b *BaggingClassifier) Votes(sample Sample) (positive, negative int) {
+	for _, classifier := range b.Classifiers {
+		if classifier.Classify(sample) {
+			positive++
+		} else {
+			negative++
+		}
+	}
+	return
+}
+
+// bootstrapResample draws len(p.Positives) positives and len(p.Negatives) negatives from a
+// Problem with replacement.
+func bootstrapResample(p *Problem, rng *rand.Rand) *Problem {
+	res := &Problem{Kernel: p.Kernel}
+	weighted := p.weighted()
+	for range p.Positives {
+		res.appendSample(p, rng.Intn(len(p.Positives)), weighted)
+	}
+	for range p.Negatives {
+		res.appendSample(p, len(p.Positives)+rng.Intn(len(p.Negatives)), weighted)
+	}
+	return res
+}
@@ -0,0 +1,52 @@
+package svm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBaggingClassifier(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 100, 2, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 20, StepSize: 0.1}
+
+	folds := crossValidationFolds(problem, 5, rng)
+	var singleAccuracy, baggingAccuracy float64
+	for i, test := range folds {
+		training := trainingFolds(folds, i)
+		singleAccuracy += Accuracy(solver.Solve(training), test) / float64(len(folds))
+		bagging := TrainBagging(training, solver, 25, rng)
+		baggingAccuracy += Accuracy(bagging, test) / float64(len(folds))
+	}
+	if baggingAccuracy < singleAccuracy {
+		t.Errorf("bagging accuracy %f is worse than single accuracy %f", baggingAccuracy,
+			singleAccuracy)
+	}
+
+	bagging := TrainBagging(problem, solver, 25, rng)
+	for _, sample := range problem.Positives[:20] {
+		positive, negative := bagging.Votes(sample)
+		if positive+negative != len(bagging.Classifiers) {
+			t.Fatal("votes do not add up to the number of classifiers")
+		}
+		if bagging.Classify(sample) != (positive > negative) {
+			t.Error("classification disagrees with majority vote")
+		}
+		expected := float64(positive-negative) / float64(len(bagging.Classifiers))
+		if bagging.Rating(sample) != expected {
+			t.Error("unexpected rating")
+		}
+	}
+}
+
+func TestBaggingClassifierReproducible(t *testing.T) {
+	problem := noisyLinearProblem(rand.New(rand.NewSource(1)), 50, 2, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 50, StepSize: 0.05}
+	b1 := TrainBagging(problem, solver, 5, rand.New(rand.NewSource(2)))
+	b2 := TrainBagging(problem, solver, 5, rand.New(rand.NewSource(2)))
+	for i, c := range b1.Classifiers {
+		if c.Threshold != b2.Classifiers[i].Threshold {
+			t.Fatal("classifiers differ for the same seed")
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package svm
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	plattMaxIterations = 100
+	plattMinStep       = 1e-10
+	plattHessianOffset = 1e-12
+	plattGradientEps   = 1e-5
+)
+
+// A Calibrator maps the decision values of a classifier to probabilities.
+type Calibrator interface {
+	// Probability returns the probability that a sample with the given decision value is
+	// positive.
+	Probability(decisionValue float64) float64
+}
+
+// A ProbabilisticClassifier is a Classifier whose decision values have been calibrated to produce
+// probabilities.
+type ProbabilisticClassifier struct {
+	Classifier Classifier
+	Calibrator Calibrator
+}
+
+func (p *ProbabilisticClassifier) Classify(sample Sample) bool {
+	return p.Classifier.Classify(sample)
+}
+
+func (p *ProbabilisticClassifier) Rating(sample Sample) float64 {
+	return p.Classifier.Rating(sample)
+}
+
+// Probability returns the calibrated probability that a sample is positive.
+func (p *ProbabilisticClassifier) Probability(sample Sample) float64 {
+	return p.Calibrator.Probability(p.Classifier.Rating(sample))
+}
+
+// Predict classifies a sample, returning its classification, its Rating, and the calibrated
+// probability that it is positive.
+func (p *ProbabilisticClassifier) Predict(sample Sample) Prediction {
+	value := p.Classifier.Rating(sample)
+	return Prediction{
+		Positive:      p.Classifier.Classify(sample),
+		DecisionValue: value,
+		Probability:   p.Calibrator.Probability(value),
+	}
+}
+
+// A PlattCalibrator maps a decision value f to the probability 1/(1+exp(A*f+B)).
+type PlattCalibrator struct {
+	A float64
+	B float64
+}
+
+func (p *PlattCalibrator) Probability(decisionValue float64) float64 {
+	fApB := decisionValue*p.A + p.B
+	if fApB >= 0 {
+		return math.Exp(-fApB) / (1 + math.Exp(-fApB))
+	}
+	return 1 / (1 + math.Exp(fApB))
+}
+
+// FitPlatt calibrates a classifier using Platt scaling, which fits a sigmoid to the decision
+// values of the classifier on the samples of a Problem.
+//
+// The sigmoid is fit using the Newton method from Lin, Lin, and Weng's "A Note on Platt's
+// Probabilistic Outputs for Support Vector Machines".
+// As Platt recommends, the targets are not exactly 0 and 1, but rather 1/(N-+2) and
+// (N++1)/(N++2), where N- and N+ are the number of negatives and positives.
+// This keeps the fit from overfitting, and keeps A and B finite even when all of the decision
+// values fall on one side of the boundary.
+// Ideally, the Problem should not be the same one used to train the classifier, since the
+// decision values of training samples are biased.
+func FitPlatt(c Classifier, p *Problem) *ProbabilisticClassifier {
+	decisionValues := make([]float64, p.sampleCount())
+	targets := make([]float64, p.sampleCount())
+	hiTarget := (float64(len(p.Positives)) + 1) / (float64(len(p.Positives)) + 2)
+	loTarget := 1 / (float64(len(p.Negatives)) + 2)
+	for i := range decisionValues {
+		sample, sign := p.sample(i)
+		decisionValues[i] = c.Rating(sample)
+		if sign > 0 {
+			targets[i] = hiTarget
+		} else {
+			targets[i] = loTarget
+		}
+	}
+
+	calibrator := &PlattCalibrator{
+		B: math.Log((float64(len(p.Negatives)) + 1) / (float64(len(p.Positives)) + 1)),
+	}
+	loss := plattLoss(calibrator, decisionValues, targets)
+
+	for iter := 0; iter < plattMaxIterations; iter++ {
+		h11, h22, h21 := plattHessianOffset, plattHessianOffset, 0.0
+		var g1, g2 float64
+		for i, f := range decisionValues {
+			prob := calibrator.Probability(f)
+			d2 := prob * (1 - prob)
+			h11 += f * f * d2
+			h22 += d2
+			h21 += f * d2
+			d1 := targets[i] - prob
+			g1 += f * d1
+			g2 += d1
+		}
+		if math.Abs(g1) < plattGradientEps && math.Abs(g2) < plattGradientEps {
+			break
+		}
+
+		det := h11*h22 - h21*h21
+		dA := -(h22*g1 - h21*g2) / det
+		dB := -(-h21*g1 + h11*g2) / det
+		gd := g1*dA + g2*dB
+
+		stepSize := 1.0
+		for stepSize >= plattMinStep {
+			newCalibrator := &PlattCalibrator{
+				A: calibrator.A + stepSize*dA,
+				B: calibrator.B + stepSize*dB,
+			}
+			newLoss := plattLoss(newCalibrator, decisionValues, targets)
+			if newLoss < loss+0.0001*stepSize*gd {
+				calibrator = newCalibrator
+				loss = newLoss
+				break
+			}
+			stepSize /= 2
+		}
+		if stepSize < plattMinStep {
+			break
+		}
+	}
+
+	return &ProbabilisticClassifier{Classifier: c, Calibrator: calibrator}
+}
+
+// plattLoss computes the cross-entropy loss of a PlattCalibrator.
+func plattLoss(c *PlattCalibrator, decisionValues, targets []float64) float64 {
+	var loss float64
+	for i, f := range decisionValues {
+		fApB := f*c.A + c.B
+		if fApB >= 0 {
+			loss += targets[i]*fApB + math.Log(1+math.Exp(-fApB))
+		} else {
+			loss += (targets[i]-1)*fApB + math.Log(1+math.Exp(fApB))
+		}
+	}
+	return loss
+}
+
+// An IsotonicCalibrator maps decision values to probabilities using a non-decreasing step
+// function.
+type IsotonicCalibrator struct {
+	// Boundaries contains the smallest decision value of each step, in ascending order.
+	Boundaries []float64
+
+	// Probabilities contains the probability for each step.
+	Probabilities []float64
+}
+
+// Probability returns the probability of the last step whose boundary is at most the decision
+// value.
+// Decision values below the first boundary get the probability of the first step.
+// If the calibrator has no steps, as when it is fit on a Problem with no samples, the probability
+// is NaN.
+func (i *IsotonicCalibrator) Probability(decisionValue float64) float64 {
+	if len(i.Probabilities) == 0 {
+		return math.NaN()
+	}
+	idx := sort.Search(len(i.Boundaries), func(j int) bool {
+		return i.Boundaries[j] > decisionValue
+	})
+	if idx == 0 {
+		return i.Probabilities[0]
+	}
+	return i.Probabilities[idx-1]
+}
+
+// FitIsotonic calibrates a classifier using isotonic regression, which fits the non-decreasing
+// function of the decision values which best matches the labels of the samples of a Problem.
+//
+// The function is fit using the pool-adjacent-violators algorithm.
+// Unlike FitPlatt, this makes no assumption about the shape of the mapping, so it can handle
+// decision values which are not distributed like a sigmoid.
+// On the other hand, it needs more samples to fit well.
+// As with FitPlatt, the Problem should ideally not be the same one used to train the classifier.
+func FitIsotonic(c Classifier, p *Problem) *ProbabilisticClassifier {
+	decisionValues := make([]float64, p.sampleCount())
+	targets := make([]float64, p.sampleCount())
+	for i := range decisionValues {
+		sample, sign := p.sample(i)
+		decisionValues[i] = c.Rating(sample)
+		if sign > 0 {
+			targets[i] = 1
+		}
+	}
+	order := make([]int, len(decisionValues))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return decisionValues[order[i]] < decisionValues[order[j]]
+	})
+
+	// Each block stores the mean target of a run of samples, and samples with equal decision
+	// values always start out in the same block.
+	type block struct {
+		boundary float64
+		sum      float64
+		count    float64
+	}
+	var blocks []block
+	for _, idx := range order {
+		value := decisionValues[idx]
+		if len(blocks) > 0 && blocks[len(blocks)-1].boundary == value {
+			blocks[len(blocks)-1].sum += targets[idx]
+			blocks[len(blocks)-1].count++
+		} else {
+			blocks = append(blocks, block{boundary: value, sum: targets[idx], count: 1})
+		}
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.sum/prev.count < last.sum/last.count {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1].sum += last.sum
+			blocks[len(blocks)-1].count += last.count
+		}
+	}
+
+	calibrator := &IsotonicCalibrator{}
+	for _, b := range blocks {
+		calibrator.Boundaries = append(calibrator.Boundaries, b.boundary)
+		calibrator.Probabilities = append(calibrator.Probabilities, b.sum/b.count)
+	}
+	return &ProbabilisticClassifier{Classifier: c, Calibrator: calibrator}
+}
@@ -0,0 +1,135 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFitPlatt(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 200, 2, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.01, Steps: 200, StepSize: 0.001}
+	classifier := solver.Solve(problem)
+
+	calibrated := FitPlatt(classifier, problem)
+	if prob := calibrated.Calibrator.Probability(0); math.Abs(prob-0.5) > 0.1 {
+		t.Error("probability at boundary should be near 0.5 but got", prob)
+	}
+
+	last := calibrated.Calibrator.Probability(-5)
+	for f := -4.9; f <= 5; f += 0.1 {
+		prob := calibrated.Calibrator.Probability(f)
+		if prob <= last {
+			t.Fatalf("probability is not increasing at %f", f)
+		}
+		last = prob
+	}
+
+	for _, x := range problem.Positives[:10] {
+		expected := calibrated.Calibrator.Probability(classifier.Rating(x))
+		if calibrated.Probability(x) != expected {
+			t.Error("unexpected sample probability")
+		}
+	}
+}
+
+func TestFitPlattSeparable(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{1}}, {V: []float64{2}}},
+		Negatives: []Sample{{V: []float64{3}}, {V: []float64{4}}},
+		Kernel:    LinearKernel,
+	}
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{1}},
+		Kernel:           LinearKernel,
+	}
+	calibrated := FitPlatt(classifier, problem)
+	for _, x := range []float64{-10, 0, 10} {
+		prob := calibrated.Calibrator.Probability(x)
+		if math.IsNaN(prob) || prob <= 0 || prob >= 1 {
+			t.Errorf("bad probability %f for decision value %f", prob, x)
+		}
+	}
+}
+
+func TestFitIsotonic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem, test := noisyLinearProblem(rng, 400, 2, 0.2).Split(0.5, rng)
+	solver := &SubgradientSolver{Tradeoff: 0.01, Steps: 200, StepSize: 0.001}
+	classifier := solver.Solve(problem)
+	calibrated := FitIsotonic(classifier, problem)
+
+	calibrator := calibrated.Calibrator.(*IsotonicCalibrator)
+	for i := 1; i < len(calibrator.Probabilities); i++ {
+		if calibrator.Probabilities[i] < calibrator.Probabilities[i-1] {
+			t.Fatal("probabilities are not non-decreasing:", calibrator.Probabilities)
+		}
+		if calibrator.Boundaries[i] <= calibrator.Boundaries[i-1] {
+			t.Fatal("boundaries are not increasing:", calibrator.Boundaries)
+		}
+	}
+	last := calibrated.Calibrator.Probability(-5)
+	for f := -4.9; f <= 5; f += 0.1 {
+		prob := calibrated.Calibrator.Probability(f)
+		if prob < last || prob < 0 || prob > 1 {
+			t.Fatalf("bad probability %f at %f", prob, f)
+		}
+		last = prob
+	}
+
+	// Compare the Brier scores of the calibrated probabilities and of the decision values
+	// clipped to [0, 1] on held-out samples.
+	var calibratedScore, rawScore float64
+	for i := 0; i < test.sampleCount(); i++ {
+		sample, sign := test.sample(i)
+		target := math.Max(0, sign)
+		raw := math.Max(0, math.Min(1, (classifier.Rating(sample)+1)/2))
+		calibratedScore += math.Pow(calibrated.Probability(sample)-target, 2)
+		rawScore += math.Pow(raw-target, 2)
+	}
+	if calibratedScore >= rawScore {
+		t.Errorf("calibrated Brier score %f is no better than raw score %f", calibratedScore,
+			rawScore)
+	}
+}
+
+func TestFitIsotonicEmpty(t *testing.T) {
+	classifier := &LinearClassifier{HyperplaneNormal: NewSample(1), Kernel: LinearKernel}
+	calibrated := FitIsotonic(classifier, &Problem{Kernel: LinearKernel})
+	if p := calibrated.Probability(NewSample(1)); !math.IsNaN(p) {
+		t.Error("expected NaN but got", p)
+	}
+	if p := (&IsotonicCalibrator{}).Probability(0); !math.IsNaN(p) {
+		t.Error("expected NaN but got", p)
+	}
+}
+
+func TestFitIsotonicTies(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{1}}, {V: []float64{2}}},
+		Negatives: []Sample{{V: []float64{1}}, {V: []float64{0}}},
+		Kernel:    LinearKernel,
+	}
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{1}},
+		Kernel:           LinearKernel,
+	}
+	calibrator := FitIsotonic(classifier, problem).Calibrator
+	expected := map[float64]float64{-1: 0, 0: 0, 1: 0.5, 1.5: 0.5, 2: 1, 3: 1}
+	for f, prob := range expected {
+		if actual := calibrator.Probability(f); actual != prob {
+			t.Errorf("expected probability %f at %f but got %f", prob, f, actual)
+		}
+	}
+}
+
+// noisyLinearProblem generates a Problem like randomLinearProblem, but with a fraction of the
+// samples moved to the wrong class.
+func noisyLinearProblem(rng *rand.Rand, count, dim int, noise float64) *Problem {
+	res := randomLinearProblem(rng, count, dim, 0)
+	for i := 0; i < int(noise*float64(count)); i++ {
+		res.Positives[i], res.Negatives[i] = res.Negatives[i], res.Positives[i]
+	}
+	return res
+}
@@ -0,0 +1,88 @@
+package svm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// checkpointData is the JSON representation of a SubgradientSolver checkpoint.
+type checkpointData struct {
+	Normal            []float64 `json:"normal"`
+	Threshold         float64   `json:"threshold"`
+	Velocity          []float64 `json:"velocity"`
+	VelocityThreshold float64   `json:"velocityThreshold"`
+	Step              int       `json:"step"`
+}
+
+// checkpoint writes state to s.Checkpoint if it is set and a checkpoint is due.
+func (s *SubgradientSolver) checkpoint(state *descentState) error {
+	if s.Checkpoint == nil {
+		return nil
+	}
+	every := s.CheckpointEvery
+	if every == 0 {
+		every = 1
+	}
+	if state.step%every != 0 {
+		return nil
+	}
+	return json.NewEncoder(s.Checkpoint).Encode(&checkpointData{
+		Normal:            state.args.normal,
+		Threshold:         state.args.threshold,
+		Velocity:          state.velocity.normal,
+		VelocityThreshold: state.velocity.threshold,
+		Step:              state.step,
+	})
+}
+
+// Resume continues training from the last checkpoint in r, which must have been written by a
+// SubgradientSolver solving the same Problem (see Checkpoint).
+//
+// The descent picks up at the step after the checkpoint and runs until s.Steps steps have been
+// taken in total, so a StepSchedule sees the same step indices as it would have in an
+// uninterrupted run.
+// The checkpoint stores the normal, threshold, and Momentum velocity, but not the state of
+// s.Optimizer or of early stopping, both of which start over.
+//
+// Resume returns an error if p is invalid, if r contains no valid checkpoint, or if the
+// checkpoint does not match the dimension of p.
+// Otherwise, it behaves like SolveE.
+func (s *SubgradientSolver) Resume(r io.Reader, p *Problem) (*LinearClassifier, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	var data *checkpointData
+	decoder := json.NewDecoder(r)
+	for {
+		var next checkpointData
+		if err := decoder.Decode(&next); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("read checkpoint: %w", err)
+		}
+		data = &next
+	}
+	if data == nil {
+		return nil, errors.New("no checkpoint found")
+	}
+	dim := p.Positives[0].Dim()
+	if len(data.Normal) != dim || len(data.Velocity) != dim {
+		return nil, fmt.Errorf("checkpoint has dimension %d (expected %d)", len(data.Normal), dim)
+	} else if data.Step < 0 {
+		return nil, fmt.Errorf("checkpoint has negative step %d", data.Step)
+	}
+
+	state := &descentState{
+		args:     softMarginArgs{normal: data.Normal, threshold: data.Threshold},
+		velocity: softMarginArgs{normal: data.Velocity, threshold: data.VelocityThreshold},
+		step:     data.Step,
+	}
+	res, _, err := s.solve(context.Background(), p, state)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package svm
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSubgradientResume(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 100, 3, 0.1)
+	newSolver := func(steps int) *SubgradientSolver {
+		return &SubgradientSolver{
+			Tradeoff:     0.01,
+			Steps:        steps,
+			StepSchedule: InverseTimeDecay(0.01, 0.01),
+			Momentum:     0.5,
+		}
+	}
+	const steps = 200
+	expected := newSolver(steps).Solve(problem)
+
+	var checkpoints bytes.Buffer
+	first := newSolver(steps / 2)
+	first.Checkpoint = &checkpoints
+	first.CheckpointEvery = steps / 4
+	if _, err := first.SolveE(problem); err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(checkpoints.String(), "\n"); count != 2 {
+		t.Fatalf("expected 2 checkpoints but got %d", count)
+	}
+
+	actual, err := newSolver(steps).Resume(&checkpoints, problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Threshold != expected.Threshold {
+		t.Errorf("threshold should be %f but got %f", expected.Threshold, actual.Threshold)
+	}
+	for i, x := range expected.HyperplaneNormal.V {
+		if actual.HyperplaneNormal.V[i] != x {
+			t.Errorf("component %d should be %f but got %f", i, x, actual.HyperplaneNormal.V[i])
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestSubgradientResumeErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 3, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.01, Steps: 10, StepSize: 0.01}
+	for _, checkpoint := range []string{
+		"",
+		"{",
+		`{"normal":[1,2],"velocity":[0,0],"step":3}`,
+		`{"normal":[1,2,3],"velocity":[0,0,0],"step":-1}`,
+	} {
+		if _, err := solver.Resume(strings.NewReader(checkpoint), problem); err == nil {
+			t.Errorf("expected error for %q", checkpoint)
+		}
+	}
+
+	solver.Checkpoint = failingWriter{}
+	if c, err := solver.SolveE(problem); err == nil || c != nil {
+		t.Error("expected SolveE to fail when the checkpoint cannot be written")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected Solve to panic when the checkpoint cannot be written")
+			}
+		}()
+		solver.Solve(problem)
+	}()
+}
@@ -2,10 +2,17 @@ package svm
 
 import (
 	"math"
+	"runtime"
 	"sort"
+	"sync"
 )
 
 // A Classifier classifies samples as positive or negative using some criterion.
+//
+// The classifiers in this package classify a sample as positive if and only if its Rating is
+// greater than zero, so samples which lie exactly on the decision boundary (and samples whose
+// Rating is NaN) are negative.
+// Every method which classifies samples, whether one at a time or in a batch, follows this rule.
 type Classifier interface {
 	Classify(sample Sample) bool
 
@@ -17,53 +24,325 @@ type Classifier interface {
 	Rating(sample Sample) float64
 }
 
+// A Prediction describes how a classifier classified a sample.
+type Prediction struct {
+	// Positive is the classification, as returned by Classify.
+	Positive bool
+
+	// DecisionValue is the Rating of the sample, which is the decision value for classifiers
+	// which have one.
+	DecisionValue float64
+
+	// Probability is the calibrated probability that the sample is positive.
+	// It is NaN unless the classifier is a ProbabilisticClassifier.
+	Probability float64
+}
+
+// positiveDecision returns true if a decision value or Rating indicates a positive sample.
+// Ties, where the value is exactly zero, go to the negative class.
+func positiveDecision(value float64) bool {
+	return value > 0
+}
+
 // A LinearClassifier classifies samples using a hyperplane normal whose pre-image is known.
 // This can only be used with solvers that generate a solution which is not inside the transformed
 // space represented by the Kernel.
+//
+// Classify, Rating, DecisionValue, and the other methods which classify samples only read the
+// classifier, so a LinearClassifier is safe for concurrent use by multiple goroutines once it has
+// been constructed, as long as its Kernel is.
+// Every Kernel in this package is safe for concurrent use.
+// Methods which modify the classifier, such as Update, must not be called concurrently with any
+// other method.
 type LinearClassifier struct {
 	HyperplaneNormal Sample
 	Threshold        float64
 	Kernel           Kernel
+
+	// KernelName is the name of Kernel as accepted by NamedKernel.
+	// It is needed to serialize the classifier, unless Kernel is LinearKernel.
+	KernelName string
 }
 
+// Classify returns true if the DecisionValue of the sample is positive.
+// A sample on the boundary, where the DecisionValue is exactly zero, is negative.
 func (c *LinearClassifier) Classify(sample Sample) bool {
-	return c.Rating(sample) > 0
+	return positiveDecision(c.DecisionValue(sample))
+}
+
+// Predict classifies a sample, returning both its classification and its decision value.
+// This only evaluates the Kernel once.
+func (c *LinearClassifier) Predict(sample Sample) Prediction {
+	value := c.DecisionValue(sample)
+	return Prediction{
+		Positive:      positiveDecision(value),
+		DecisionValue: value,
+		Probability:   math.NaN(),
+	}
 }
 
+// Rating is equivalent to DecisionValue.
 func (c *LinearClassifier) Rating(sample Sample) float64 {
+	return c.DecisionValue(sample)
+}
+
+// DecisionValue returns the signed margin of a sample, Kernel(sample, HyperplaneNormal) plus the
+// Threshold.
+// This is positive for samples which the classifier deems positive.
+func (c *LinearClassifier) DecisionValue(sample Sample) float64 {
 	dot := c.Kernel(sample, c.HyperplaneNormal)
 	return dot + c.Threshold
 }
 
+// Distance returns the signed Euclidean distance from a sample to the separating hyperplane.
+// Positive distances correspond to positive classifications.
+//
+// This is only geometrically meaningful for LinearKernel, so it returns NaN for other kernels.
+func (c *LinearClassifier) Distance(sample Sample) float64 {
+	if !isLinearKernel(c.Kernel) {
+		return math.NaN()
+	}
+	return c.DecisionValue(sample) / c.normalMagnitude()
+}
+
+// Update performs one step of online sub-gradient descent on the hinge loss of a single labeled
+// sample, modifying HyperplaneNormal and Threshold in place.
+// Samples which are already classified correctly and outside of the margin cause no change.
+//
+// Used with a decaying StepSchedule, repeated updates allow a classifier to be refined as new
+// data arrives, without re-training it from scratch.
+//
+// This panics if the Kernel is not LinearKernel, since the hinge loss is only linear in the
+// normal for that kernel.
+func (c *LinearClassifier) Update(s Sample, positive bool, stepSize float64) {
+	if !isLinearKernel(c.Kernel) {
+		panic("online updates require LinearKernel")
+	}
+	sign := -1.0
+	if positive {
+		sign = 1
+	}
+	if sign*c.DecisionValue(s) >= 1 {
+		return
+	}
+	addScaled(c.HyperplaneNormal.V, s, stepSize*sign)
+	c.Threshold += stepSize * sign
+}
+
+// MarginWidth returns the width of the slab between the hyperplanes where the decision value is
+// -1 and 1, which is 2/|HyperplaneNormal|.
+// A larger Tradeoff generally produces a wider margin.
+//
+// Like Distance, this is only geometrically meaningful for LinearKernel, so it returns NaN for
+// other kernels.
+func (c *LinearClassifier) MarginWidth() float64 {
+	if !isLinearKernel(c.Kernel) {
+		return math.NaN()
+	}
+	return 2 / c.normalMagnitude()
+}
+
+// SupportVectors approximates the support vectors of the classifier by returning the samples of a
+// Problem which lie on or inside the margin, where |DecisionValue| <= 1.
+//
+// Unlike the support vectors of a CombinationClassifier, which are exactly the samples with
+// non-zero dual coefficients, this is based purely on the geometry of the solution.
+// For an approximate solution (such as one from SubgradientSolver), samples very close to the
+// margin may be included or excluded incorrectly.
+func (c *LinearClassifier) SupportVectors(p *Problem) []Sample {
+	var res []Sample
+	for i := 0; i < p.sampleCount(); i++ {
+		sample, _ := p.sample(i)
+		if math.Abs(c.DecisionValue(sample)) <= 1 {
+			res = append(res, sample)
+		}
+	}
+	return res
+}
+
+// MarginViolations counts the positives and negatives in a Problem which violate the margin,
+// meaning that they are misclassified or lie inside the margin, where 1 or -1 times the
+// DecisionValue (for positives or negatives, respectively) is less than 1.
+// These are the samples with a non-zero hinge loss, which contribute to the error margins
+// minimized by SubgradientSolver.
+//
+// Increasing the Tradeoff of a solver widens the margin at the expense of more violations, so
+// these counts help show whether a Tradeoff is too large.
+// Samples exactly on the margin are not counted, but an approximate solution may misplace
+// samples very close to it.
+func (c *LinearClassifier) MarginViolations(p *Problem) (positives, negatives int) {
+	for i := 0; i < p.sampleCount(); i++ {
+		sample, sign := p.sample(i)
+		if sign*c.DecisionValue(sample) < 1 {
+			if sign > 0 {
+				positives++
+			} else {
+				negatives++
+			}
+		}
+	}
+	return
+}
+
+// FeatureImportances returns the absolute value of each component of HyperplaneNormal.
+//
+// For LinearKernel, a larger value means that the corresponding feature has a larger effect on
+// the decision value.
+// This is only a fair comparison if the features have the same scale, so the features should be
+// standardized (see FitStandardizer) before training.
+// For other kernels, the components of the normal have no such interpretation.
+func (c *LinearClassifier) FeatureImportances() []float64 {
+	res := make([]float64, len(c.HyperplaneNormal.V))
+	for i, x := range c.HyperplaneNormal.V {
+		res[i] = math.Abs(x)
+	}
+	return res
+}
+
+// TopFeatures returns the indices of the n most important features according to
+// FeatureImportances, starting with the most important one.
+// Features with equal importance are ordered by index.
+// If n is larger than the number of features, every feature is returned, and if n is zero or
+// negative, no features are returned.
+func (c *LinearClassifier) TopFeatures(n int) []int {
+	importances := c.FeatureImportances()
+	res := make([]int, len(importances))
+	for i := range res {
+		res[i] = i
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return importances[res[i]] > importances[res[j]]
+	})
+	if n < 0 {
+		n = 0
+	}
+	if n < len(res) {
+		res = res[:n]
+	}
+	return res
+}
+
+func (c *LinearClassifier) normalMagnitude() float64 {
+	return math.Sqrt(LinearKernel(c.HyperplaneNormal, c.HyperplaneNormal))
+}
+
+// DecisionValues computes the decision value for each of the samples.
+func (c *LinearClassifier) DecisionValues(samples []Sample) []float64 {
+	res := make([]float64, len(samples))
+	for i, sample := range samples {
+		res[i] = c.DecisionValue(sample)
+	}
+	return res
+}
+
+// ClassifyBatch classifies each of the samples, with the same results as Classify.
+func (c *LinearClassifier) ClassifyBatch(samples []Sample) []bool {
+	res := make([]bool, len(samples))
+	for i, value := range c.DecisionValues(samples) {
+		res[i] = positiveDecision(value)
+	}
+	return res
+}
+
+// ClassifyParallel is like ClassifyBatch, but it splits the samples into contiguous ranges and
+// classifies each range on a separate goroutine.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) goroutines are used.
+//
+// The i-th result always corresponds to the i-th sample, regardless of how the goroutines are
+// scheduled.
+// The classifier is only read, so this is safe as long as the Kernel is safe to use from
+// multiple goroutines.
+func (c *LinearClassifier) ClassifyParallel(samples []Sample, workers int) []bool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	res := make([]bool, len(samples))
+	chunkSize := (len(samples) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < len(samples); start += chunkSize {
+		end := start + chunkSize
+		if end > len(samples) {
+			end = len(samples)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				res[i] = c.Classify(samples[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	return res
+}
+
 // A CombinationClassifier classifies novel samples by taking their inner product with a hyperplane
 // normal that is a linear combination of support vectors.
 // This employs a "kernel trick" to avoid needing to know the actual vector transformation.
+//
+// When produced by SMOSolver, SupportVectors contains exactly the samples with non-zero dual
+// coefficients, which are the samples lying on or inside the margin.
+// These are the only samples which affect the solution.
 type CombinationClassifier struct {
 	SupportVectors []Sample
-	Coefficients   []float64
+
+	// Coefficients contains the coefficient of each support vector, which is its dual coefficient
+	// (Lagrange multiplier) times 1 for positives or -1 for negatives.
+	Coefficients []float64
 
 	Threshold float64
 	Kernel    Kernel
 }
 
 func (c *CombinationClassifier) Classify(sample Sample) bool {
-	return c.Rating(sample) > 0
+	return positiveDecision(c.Rating(sample))
 }
 
 func (c *CombinationClassifier) Rating(sample Sample) float64 {
 	return c.sampleProduct(sample) + c.Threshold
 }
 
+// Predict classifies a sample, returning both its classification and its Rating.
+func (c *CombinationClassifier) Predict(sample Sample) Prediction {
+	value := c.Rating(sample)
+	return Prediction{
+		Positive:      positiveDecision(value),
+		DecisionValue: value,
+		Probability:   math.NaN(),
+	}
+}
+
+// Dual returns the parameters of the classifier's dual form, which is what other SVM libraries
+// (such as the dual_coef_, support_vectors_, and intercept_ of scikit-learn) need to represent
+// the same model.
+//
+// The i-th coefficient belongs to the i-th support vector, and is the support vector's dual
+// coefficient (alpha_i) times 1 for positives or -1 for negatives (y_i).
+// The Rating of a sample x is then the sum of coefficients[i]*Kernel(supportVectors[i], x),
+// plus bias.
+// The support vectors are in the same order as c.SupportVectors, which SMOSolver fills with the
+// positives before the negatives.
+//
+// The returned slices are copies, so modifying them does not affect the classifier.
+func (c *CombinationClassifier) Dual() (supportVectors []Sample, coefficients []float64,
+	bias float64) {
+	supportVectors = make([]Sample, len(c.SupportVectors))
+	for i, vec := range c.SupportVectors {
+		supportVectors[i] = vec.Clone()
+	}
+	coefficients = append([]float64{}, c.Coefficients...)
+	return supportVectors, coefficients, c.Threshold
+}
+
 // Linearize converts a CombinationClassifier into a LinearClassifier, assuming that the underlying
 // kernel is LinearKernel.
 // This will not work for non-linear kernels.
 func (c *CombinationClassifier) Linearize() *LinearClassifier {
-	sampleSum := make([]float64, len(c.SupportVectors[0].V))
+	sampleSum := make([]float64, c.SupportVectors[0].Dim())
 	for i, vec := range c.SupportVectors {
-		coeff := c.Coefficients[i]
-		for j := range sampleSum {
-			sampleSum[j] += coeff * vec.V[j]
-		}
+		addScaled(sampleSum, vec, c.Coefficients[i])
 	}
 	return &LinearClassifier{
 		Kernel:           c.Kernel,
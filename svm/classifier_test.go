@@ -0,0 +1,425 @@
+package svm
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestLinearClassifierDecisionValue(t *testing.T) {
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{1, 1}},
+		Threshold:        -1,
+		Kernel:           LinearKernel,
+	}
+	for _, sample := range []Sample{{V: []float64{3, 2}}, {V: []float64{10, -5}}} {
+		if classifier.DecisionValue(sample) <= 0 {
+			t.Error("expected positive decision value for", sample.V)
+		}
+	}
+	if value := classifier.DecisionValue(Sample{V: []float64{2, 3}}); value != 4 {
+		t.Error("expected decision value 4 but got", value)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		sample := gaussianSample(rng, 2)
+		if (classifier.DecisionValue(sample) > 0) != classifier.Classify(sample) {
+			t.Error("decision value disagrees with Classify for", sample.V)
+		}
+	}
+}
+
+func TestLinearClassifierBatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	classifier := &LinearClassifier{
+		HyperplaneNormal: gaussianSample(rng, 3),
+		Threshold:        0.1,
+		Kernel:           LinearKernel,
+	}
+	samples := make([]Sample, 50)
+	for i := range samples {
+		samples[i] = gaussianSample(rng, 3)
+	}
+
+	values := classifier.DecisionValues(samples)
+	classes := classifier.ClassifyBatch(samples)
+	if len(values) != len(samples) || len(classes) != len(samples) {
+		t.Fatal("unexpected output lengths")
+	}
+	for i, sample := range samples {
+		if values[i] != classifier.DecisionValue(sample) {
+			t.Errorf("sample %d: unexpected decision value", i)
+		}
+		if classes[i] != classifier.Classify(sample) {
+			t.Errorf("sample %d: unexpected classification", i)
+		}
+	}
+}
+
+func TestClassifierTieBreak(t *testing.T) {
+	// With integer features, the decision value is exactly zero on the boundary.
+	linear := &LinearClassifier{
+		HyperplaneNormal: NewSample(2, -1),
+		Threshold:        -1,
+		Kernel:           LinearKernel,
+	}
+	boundary := NewSample(3, 5)
+	samples := []Sample{NewSample(3, 4), boundary, NewSample(3, 6)}
+	if value := linear.DecisionValue(boundary); value != 0 {
+		t.Fatal("sample should lie on the boundary but has decision value", value)
+	}
+	expected := []bool{true, false, false}
+	if linear.Classify(boundary) {
+		t.Error("Classify should be negative on the boundary")
+	}
+	if actual := linear.ClassifyBatch(samples); !reflect.DeepEqual(actual, expected) {
+		t.Error("unexpected ClassifyBatch results:", actual)
+	}
+	if actual := linear.ClassifyParallel(samples, 2); !reflect.DeepEqual(actual, expected) {
+		t.Error("unexpected ClassifyParallel results:", actual)
+	}
+
+	combination := &CombinationClassifier{
+		SupportVectors: []Sample{NewSample(1, 0), NewSample(0, 1)},
+		Coefficients:   []float64{2, -1},
+		Threshold:      -1,
+		Kernel:         LinearKernel,
+	}
+	for _, c := range []Classifier{combination, NewFloat32Classifier(combination)} {
+		if c.Rating(boundary) != 0 || c.Classify(boundary) {
+			t.Errorf("%T: boundary sample should be negative", c)
+		}
+	}
+}
+
+func TestPredict(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 50, 2, 0.1)
+	linear := (&SubgradientSolver{Tradeoff: 0.01, Steps: 100, StepSize: 0.001}).Solve(problem)
+	combination := (&SMOSolver{Tradeoff: 0.01}).Solve(problem)
+	calibrated := FitPlatt(linear, problem)
+
+	predictors := map[string]interface {
+		Classifier
+		Predict(sample Sample) Prediction
+	}{
+		"linear":      linear,
+		"combination": combination,
+		"calibrated":  calibrated,
+	}
+	for name, c := range predictors {
+		for i := 0; i < problem.sampleCount(); i++ {
+			sample, _ := problem.sample(i)
+			prediction := c.Predict(sample)
+			if prediction.Positive != c.Classify(sample) ||
+				prediction.DecisionValue != c.Rating(sample) {
+				t.Errorf("%s: sample %d: inconsistent prediction %+v", name, i, prediction)
+			}
+			if name == "calibrated" {
+				if prediction.Probability != calibrated.Probability(sample) {
+					t.Errorf("sample %d: unexpected probability %f", i, prediction.Probability)
+				}
+			} else if !math.IsNaN(prediction.Probability) {
+				t.Errorf("%s: probability should be NaN", name)
+			}
+		}
+	}
+	if prediction := linear.Predict(linear.HyperplaneNormal); prediction.DecisionValue !=
+		linear.DecisionValue(linear.HyperplaneNormal) {
+		t.Error("unexpected decision value:", prediction.DecisionValue)
+	}
+}
+
+func TestLinearClassifierDistance(t *testing.T) {
+	// The line 3x + 4y = 5.
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{3, 4}},
+		Threshold:        -5,
+		Kernel:           LinearKernel,
+	}
+	expected := map[[2]float64]float64{
+		{0, 0}:     -1,
+		{3, 4}:     4,
+		{-1, 2}:    0,
+		{-1, -0.5}: -2,
+	}
+	for point, distance := range expected {
+		actual := classifier.Distance(Sample{V: point[:]})
+		if math.Abs(actual-distance) > 1e-12 {
+			t.Errorf("distance to %v should be %f but got %f", point, distance, actual)
+		}
+	}
+
+	classifier.Kernel = PolynomialKernel(1, 2)
+	if !math.IsNaN(classifier.Distance(Sample{V: []float64{1, 1}})) {
+		t.Error("expected NaN for a non-linear kernel")
+	}
+}
+
+func TestLinearClassifierUpdate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 200, 3, 0.1)
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: make([]float64, 3)},
+		Kernel:           LinearKernel,
+	}
+	schedule := InverseTimeDecay(0.5, 0.01)
+	for step := 0; step < 5000; step++ {
+		sample, sign := problem.sample(rng.Intn(problem.sampleCount()))
+		classifier.Update(sample, sign > 0, schedule(step))
+	}
+	if accuracy := problemAccuracy(classifier, problem); accuracy != 1 {
+		t.Error("unexpected accuracy:", accuracy)
+	}
+}
+
+func TestLinearClassifierUpdateKernel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non-linear kernel")
+		}
+	}()
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{1}},
+		Kernel:           PolynomialKernel(1, 2),
+	}
+	classifier.Update(Sample{V: []float64{1}}, true, 0.1)
+}
+
+func TestLinearClassifierMarginWidth(t *testing.T) {
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{3, 4}},
+		Threshold:        2,
+		Kernel:           LinearKernel,
+	}
+	if actual := classifier.MarginWidth(); math.Abs(actual-0.4) > 1e-12 {
+		t.Error("expected 0.4 but got", actual)
+	}
+
+	// The margin hyperplanes should be exactly MarginWidth apart.
+	lower := classifier.Distance(Sample{V: []float64{0, -0.75}})
+	upper := classifier.Distance(Sample{V: []float64{0, -0.25}})
+	if math.Abs(upper-lower-classifier.MarginWidth()) > 1e-12 {
+		t.Errorf("margins are %f apart", upper-lower)
+	}
+
+	classifier.Kernel = PolynomialKernel(1, 2)
+	if !math.IsNaN(classifier.MarginWidth()) {
+		t.Error("expected NaN for non-linear kernel")
+	}
+}
+
+func TestSupportVectors(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{1, 0}}, {V: []float64{3, 0}}, {V: []float64{3, 1}}},
+		Negatives: []Sample{{V: []float64{-1, 0}}, {V: []float64{-3, 0}}, {V: []float64{-3, -1}}},
+		Kernel:    LinearKernel,
+	}
+	expected := []Sample{problem.Positives[0], problem.Negatives[0]}
+
+	solver := &SMOSolver{Tradeoff: 0.001, Tolerance: 1e-6}
+	dual := solver.Solve(problem)
+	if !reflect.DeepEqual(dual.SupportVectors, expected) {
+		t.Error("unexpected dual support vectors:", dual.SupportVectors)
+	}
+	if len(dual.Coefficients) != 2 || dual.Coefficients[0] <= 0 || dual.Coefficients[1] >= 0 {
+		t.Error("unexpected coefficients:", dual.Coefficients)
+	}
+
+	linear := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{1, 0}},
+		Kernel:           LinearKernel,
+	}
+	if actual := linear.SupportVectors(problem); !reflect.DeepEqual(actual, expected) {
+		t.Error("unexpected margin support vectors:", actual)
+	}
+}
+
+func TestLinearClassifierTopFeatures(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &Problem{Kernel: LinearKernel}
+	for i := 0; i < 200; i++ {
+		sample := gaussianSample(rng, 5)
+		if sample.V[2] > 0 {
+			problem.Positives = append(problem.Positives, sample)
+		} else {
+			problem.Negatives = append(problem.Negatives, sample)
+		}
+	}
+	solver := &SubgradientSolver{Tradeoff: 0.01, Steps: 200, StepSize: 0.001}
+	classifier := solver.Solve(problem)
+
+	importances := classifier.FeatureImportances()
+	for i, x := range classifier.HyperplaneNormal.V {
+		if importances[i] != math.Abs(x) {
+			t.Errorf("importance %d should be %f but got %f", i, math.Abs(x), importances[i])
+		}
+	}
+	if top := classifier.TopFeatures(1); !reflect.DeepEqual(top, []int{2}) {
+		t.Error("decisive feature should rank first but got", top)
+	}
+	if top := classifier.TopFeatures(10); len(top) != 5 {
+		t.Error("expected every feature but got", top)
+	}
+	for _, n := range []int{0, -1} {
+		if top := classifier.TopFeatures(n); len(top) != 0 {
+			t.Errorf("n=%d: expected no features but got %v", n, top)
+		}
+	}
+
+	tied := &LinearClassifier{HyperplaneNormal: Sample{V: []float64{1, -3, 3, 0}}}
+	if top := tied.TopFeatures(3); !reflect.DeepEqual(top, []int{1, 2, 0}) {
+		t.Error("unexpected tie-breaking:", top)
+	}
+}
+
+func TestLinearClassifierClassifyParallel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]Sample, 1001)
+	for i := range samples {
+		samples[i] = gaussianSample(rng, 5)
+	}
+	classifier := &LinearClassifier{
+		HyperplaneNormal: gaussianSample(rng, 5),
+		Threshold:        0.3,
+		Kernel:           LinearKernel,
+	}
+	expected := classifier.ClassifyBatch(samples)
+	for _, workers := range []int{-2, -1, 0, 1, 3, 8, 2000} {
+		actual := classifier.ClassifyParallel(samples, workers)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%d workers: results differ from ClassifyBatch", workers)
+		}
+	}
+}
+
+func TestLinearClassifierConcurrent(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]Sample, 100)
+	for i := range samples {
+		samples[i] = gaussianSample(rng, 5)
+		samples[i].UserInfo = i + 1
+	}
+	normal := gaussianSample(rng, 5)
+	normal.UserInfo = len(samples) + 1
+	for _, kernel := range []Kernel{LinearKernel, RadialBasisKernel(0.5)} {
+		expected := (&LinearClassifier{
+			HyperplaneNormal: normal,
+			Threshold:        0.1,
+			Kernel:           kernel,
+		}).DecisionValues(samples)
+
+		// A CachedKernel is filled in by the concurrent calls themselves.
+		classifier := &LinearClassifier{
+			HyperplaneNormal: normal,
+			Threshold:        0.1,
+			Kernel:           CachedKernel(kernel),
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan string, 8)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					idx := j % len(samples)
+					if classifier.Classify(samples[idx]) != (expected[idx] > 0) ||
+						classifier.DecisionValue(samples[idx]) != expected[idx] {
+						errs <- "concurrent classification mismatch"
+						return
+					}
+				}
+				if !reflect.DeepEqual(classifier.DecisionValues(samples), expected) {
+					errs <- "concurrent batch mismatch"
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Error(err)
+		}
+	}
+}
+
+func BenchmarkLinearClassifierClassifyParallel(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]Sample, 10000)
+	for i := range samples {
+		samples[i] = gaussianSample(rng, 100)
+	}
+	classifier := &LinearClassifier{
+		HyperplaneNormal: gaussianSample(rng, 100),
+		Kernel:           RadialBasisKernel(0.01),
+	}
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("Workers%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				classifier.ClassifyParallel(samples, workers)
+			}
+		})
+	}
+}
+
+func TestCombinationClassifierDual(t *testing.T) {
+	problem := ringProblem(20, 1, 2)
+	classifier := (&SMOSolver{Tradeoff: 1}).Solve(problem)
+
+	supportVectors, coefficients, bias := classifier.Dual()
+	if len(supportVectors) == 0 || len(supportVectors) != len(coefficients) {
+		t.Fatalf("got %d support vectors and %d coefficients", len(supportVectors),
+			len(coefficients))
+	}
+	for i := 0; i < problem.sampleCount(); i++ {
+		sample, _ := problem.sample(i)
+		value := bias
+		for j, vec := range supportVectors {
+			value += coefficients[j] * problem.Kernel(vec, sample)
+		}
+		if actual := classifier.Rating(sample); math.Abs(value-actual) > 1e-8 {
+			t.Errorf("sample %d: expected rating %f but got %f", i, actual, value)
+		}
+	}
+
+	supportVectors[0].V[0]++
+	coefficients[0]++
+	if supportVectors[0].Equal(classifier.SupportVectors[0], 0) ||
+		coefficients[0] == classifier.Coefficients[0] {
+		t.Error("Dual should return copies")
+	}
+}
+
+func TestLinearClassifierMarginViolations(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{1, 0}}, {V: []float64{0.5, 0}}, {V: []float64{-1, 1}}},
+		Negatives: []Sample{{V: []float64{-2, 0}}, {V: []float64{-0.5, 0}}},
+		Kernel:    LinearKernel,
+	}
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{1, 0}},
+		Kernel:           LinearKernel,
+	}
+	if pos, neg := classifier.MarginViolations(problem); pos != 2 || neg != 1 {
+		t.Errorf("expected 2 and 1 violations but got %d and %d", pos, neg)
+	}
+
+	problem = randomLinearProblem(rand.New(rand.NewSource(1)), 50, 2, 0.2)
+	for _, tradeoff := range []float64{0.001, 1} {
+		solver := &SubgradientSolver{
+			Tradeoff:     tradeoff,
+			Steps:        2000,
+			StepSchedule: InverseTimeDecay(0.01, 0.01),
+		}
+		pos, neg := solver.Solve(problem).MarginViolations(problem)
+		if tradeoff < 0.01 && (pos != 0 || neg != 0) {
+			t.Errorf("tradeoff %f: expected no violations but got %d and %d", tradeoff, pos, neg)
+		} else if tradeoff > 0.01 && (pos == 0 || neg == 0) {
+			t.Errorf("tradeoff %f: expected violations but got %d and %d", tradeoff, pos, neg)
+		}
+	}
+}
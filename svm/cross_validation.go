@@ -0,0 +1,134 @@
+package svm
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// CrossValidate performs k-fold cross-validation of a Solver on a Problem.
+//
+// The samples are randomly divided into the given number of folds using StratifiedFolds.
+// For each fold, a classifier is trained on the samples of the other folds and tested on the
+// samples of the held-out fold.
+// The result contains the accuracy of each of these classifiers.
+//
+// The number of folds must be at least 2 and no more than the number of samples.
+func CrossValidate(p *Problem, solver Solver, folds int, rng *rand.Rand) []float64 {
+	res := make([]float64, folds)
+	for i, fold := range StratifiedFolds(p, folds, rng) {
+		res[i] = Accuracy(solver.Solve(fold[0]), fold[1])
+	}
+	return res
+}
+
+// StratifiedFolds randomly divides the samples of a Problem into folds for cross-validation,
+// returning a (training, testing) pair of Problems for each fold.
+//
+// The positives and negatives are divided separately, so the ratio of positives to negatives in
+// every testing Problem is as close as possible to the ratio in p.
+// In particular, as long as there are at least as many positives and negatives as folds, every
+// testing Problem contains both classes, which is not guaranteed by a purely random division.
+// Every sample is in exactly one testing Problem, and the training Problem of each fold contains
+// all of the samples which are not in its testing Problem.
+//
+// The number of folds must be at least 2 and no more than the number of samples.
+func StratifiedFolds(p *Problem, folds int, rng *rand.Rand) [][2]*Problem {
+	tests := crossValidationFolds(p, folds, rng)
+	res := make([][2]*Problem, folds)
+	for i, test := range tests {
+		res[i] = [2]*Problem{trainingFolds(tests, i), test}
+	}
+	return res
+}
+
+// LeaveOneOut performs leave-one-out cross-validation of a Solver on a Problem.
+//
+// For each sample, a classifier is trained on every other sample and tested on the held-out one.
+// The result is the fraction of held-out samples which were classified correctly.
+// This trains one classifier per sample, so it is best suited to small Problems.
+//
+// Each class must have at least two samples, so that no training Problem is missing a class.
+func LeaveOneOut(p *Problem, solver Solver) float64 {
+	return LeaveOneOutParallel(p, solver, 1)
+}
+
+// LeaveOneOutParallel is like LeaveOneOut, but it trains the classifiers on separate goroutines.
+// If workers is zero or negative, runtime.GOMAXPROCS(0) goroutines are used.
+//
+// The Solver must be safe to use from multiple goroutines at once.
+// For example, a PegasosSolver with a non-nil Rand is not.
+func LeaveOneOutParallel(p *Problem, solver Solver, workers int) float64 {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	indices := make(chan int, p.sampleCount())
+	for i := 0; i < p.sampleCount(); i++ {
+		indices <- i
+	}
+	close(indices)
+
+	correct := make([]bool, p.sampleCount())
+	weighted := p.weighted()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for heldOut := range indices {
+				train := &Problem{Kernel: p.Kernel}
+				for j := 0; j < p.sampleCount(); j++ {
+					if j != heldOut {
+						train.appendSample(p, j, weighted)
+					}
+				}
+				sample, sign := p.sample(heldOut)
+				correct[heldOut] = solver.Solve(train).Classify(sample) == (sign > 0)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var count int
+	for _, c := range correct {
+		if c {
+			count++
+		}
+	}
+	return float64(count) / float64(p.sampleCount())
+}
+
+// crossValidationFolds randomly divides a Problem into folds, spreading the positives and
+// negatives evenly across them.
+func crossValidationFolds(p *Problem, folds int, rng *rand.Rand) []*Problem {
+	if folds < 2 || folds > p.sampleCount() {
+		panic("invalid number of folds")
+	}
+
+	res := make([]*Problem, folds)
+	for i := range res {
+		res[i] = &Problem{Kernel: p.Kernel}
+	}
+	weighted := p.weighted()
+	for i, j := range rng.Perm(len(p.Positives)) {
+		res[i%folds].appendSample(p, j, weighted)
+	}
+	for i, j := range rng.Perm(len(p.Negatives)) {
+		res[(i+len(p.Positives))%folds].appendSample(p, j+len(p.Positives), weighted)
+	}
+	return res
+}
+
+// trainingFolds combines every fold except the held-out one into a single Problem.
+func trainingFolds(folds []*Problem, heldOut int) *Problem {
+	res := &Problem{Kernel: folds[heldOut].Kernel}
+	for i, fold := range folds {
+		if i != heldOut {
+			for j := 0; j < fold.sampleCount(); j++ {
+				res.appendSample(fold, j, fold.weighted())
+			}
+		}
+	}
+	return res
+}
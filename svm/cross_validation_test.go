@@ -0,0 +1,129 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCrossValidateFolds(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 30, 3, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+	scores := CrossValidate(problem, solver, 5, rng)
+	if len(scores) != 5 {
+		t.Fatal("expected 5 scores but got", len(scores))
+	}
+	for i, score := range scores {
+		if score < 0.8 {
+			t.Errorf("fold %d: unexpected accuracy %f", i, score)
+		}
+	}
+}
+
+func TestStratifiedFolds(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 100, 2, 0)
+	problem.Positives = problem.Positives[:7]
+	ratio := float64(len(problem.Positives)) / float64(problem.sampleCount())
+
+	folds := StratifiedFolds(problem, 5, rng)
+	if len(folds) != 5 {
+		t.Fatal("expected 5 folds but got", len(folds))
+	}
+	var testCount int
+	for i, fold := range folds {
+		train, test := fold[0], fold[1]
+		if len(test.Positives) == 0 || len(test.Negatives) == 0 {
+			t.Errorf("fold %d: test problem is missing a class", i)
+		}
+		testRatio := float64(len(test.Positives)) / float64(test.sampleCount())
+		if math.Abs(testRatio-ratio) > 0.05 {
+			t.Errorf("fold %d: positive ratio %f is far from %f", i, testRatio, ratio)
+		}
+		if train.sampleCount()+test.sampleCount() != problem.sampleCount() {
+			t.Errorf("fold %d: training and testing samples do not add up", i)
+		}
+		testCount += test.sampleCount()
+	}
+	if testCount != problem.sampleCount() {
+		t.Errorf("expected %d testing samples but got %d", problem.sampleCount(), testCount)
+	}
+}
+
+func TestCrossValidateLeaveOneOut(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 8, 2, 0.2)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+	scores := CrossValidate(problem, solver, problem.sampleCount(), rng)
+	if len(scores) != problem.sampleCount() {
+		t.Fatalf("expected %d scores but got %d", problem.sampleCount(), len(scores))
+	}
+
+	var correct float64
+	for _, score := range scores {
+		if score != 0 && score != 1 {
+			t.Fatal("every fold should hold out a single sample, but got accuracy", score)
+		}
+		correct += score
+	}
+
+	var expectedCorrect float64
+	for i := 0; i < problem.sampleCount(); i++ {
+		sample, sign := problem.sample(i)
+		train := &Problem{Kernel: problem.Kernel}
+		for j := 0; j < problem.sampleCount(); j++ {
+			if other, otherSign := problem.sample(j); j != i && otherSign > 0 {
+				train.Positives = append(train.Positives, other)
+			} else if j != i {
+				train.Negatives = append(train.Negatives, other)
+			}
+		}
+		if solver.Solve(train).Classify(sample) == (sign > 0) {
+			expectedCorrect++
+		}
+	}
+	if correct != expectedCorrect {
+		t.Errorf("expected %f correct but got %f", expectedCorrect, correct)
+	}
+}
+
+func TestLeaveOneOut(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 6, 2, 0.2)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+
+	var expectedCorrect int
+	for i := range problem.Positives {
+		train := &Problem{
+			Positives: append(append([]Sample{}, problem.Positives[:i]...),
+				problem.Positives[i+1:]...),
+			Negatives: problem.Negatives,
+			Kernel:    problem.Kernel,
+		}
+		if solver.Solve(train).Classify(problem.Positives[i]) {
+			expectedCorrect++
+		}
+	}
+	for i := range problem.Negatives {
+		train := &Problem{
+			Positives: problem.Positives,
+			Negatives: append(append([]Sample{}, problem.Negatives[:i]...),
+				problem.Negatives[i+1:]...),
+			Kernel: problem.Kernel,
+		}
+		if !solver.Solve(train).Classify(problem.Negatives[i]) {
+			expectedCorrect++
+		}
+	}
+	expected := float64(expectedCorrect) / float64(problem.sampleCount())
+
+	if actual := LeaveOneOut(problem, solver); actual != expected {
+		t.Errorf("expected accuracy %f but got %f", expected, actual)
+	}
+	for _, workers := range []int{-1, 0, 3, 100} {
+		if actual := LeaveOneOutParallel(problem, solver, workers); actual != expected {
+			t.Errorf("%d workers: expected accuracy %f but got %f", workers, expected, actual)
+		}
+	}
+}
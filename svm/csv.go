@@ -0,0 +1,71 @@
+package svm
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// LoadCSV reads a Problem from a table of comma-separated values.
+//
+// The label of each row is read from the column at index labelColumn, and every other column is
+// treated as a numeric feature.
+// Rows with positive labels are positive samples, while all other rows are negative samples.
+// Every row is read as data; see LoadCSVWithHeader for tables which start with a header row.
+//
+// Every row must have the same number of columns.
+// The Kernel of the resulting Problem is LinearKernel.
+func LoadCSV(r io.Reader, labelColumn int) (*Problem, error) {
+	return loadCSV(r, labelColumn, false)
+}
+
+// LoadCSVWithHeader is like LoadCSV, but it skips the first row of the table.
+// The header row is not checked, so it may contain column names rather than numbers.
+func LoadCSVWithHeader(r io.Reader, labelColumn int) (*Problem, error) {
+	return loadCSV(r, labelColumn, true)
+}
+
+func loadCSV(r io.Reader, labelColumn int, header bool) (*Problem, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	res := &Problem{Kernel: LinearKernel}
+	for rowNum := 1; ; rowNum++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if header && rowNum == 1 {
+			continue
+		}
+		if labelColumn < 0 || labelColumn >= len(row) {
+			return nil, fmt.Errorf("row %d: no label column %d", rowNum, labelColumn)
+		}
+
+		label, err := strconv.ParseFloat(row[labelColumn], 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: bad label: %s", rowNum, row[labelColumn])
+		}
+		sample := Sample{V: make([]float64, 0, len(row)-1)}
+		for i, cell := range row {
+			if i == labelColumn {
+				continue
+			}
+			value, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: bad value in column %d: %s", rowNum, i, cell)
+			}
+			sample.V = append(sample.V, value)
+		}
+
+		if label > 0 {
+			res.Positives = append(res.Positives, sample)
+		} else {
+			res.Negatives = append(res.Negatives, sample)
+		}
+	}
+	return res, nil
+}
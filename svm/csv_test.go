@@ -0,0 +1,63 @@
+package svm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadCSV(t *testing.T) {
+	data := "x,label,y\n" +
+		"1.5,1,2\n" +
+		"-1,-1,0.5\n" +
+		"3, 2, -4\n" +
+		"0,0,1\n"
+	problem, err := LoadCSVWithHeader(strings.NewReader(data), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPositives := [][]float64{{1.5, 2}, {3, -4}}
+	expectedNegatives := [][]float64{{-1, 0.5}, {0, 1}}
+	if len(problem.Positives) != len(expectedPositives) ||
+		len(problem.Negatives) != len(expectedNegatives) {
+		t.Fatalf("unexpected counts: %d positives, %d negatives", len(problem.Positives),
+			len(problem.Negatives))
+	}
+	for i, expected := range expectedPositives {
+		if !reflect.DeepEqual(problem.Positives[i].V, expected) {
+			t.Errorf("positive %d should be %v but got %v", i, expected, problem.Positives[i].V)
+		}
+	}
+	for i, expected := range expectedNegatives {
+		if !reflect.DeepEqual(problem.Negatives[i].V, expected) {
+			t.Errorf("negative %d should be %v but got %v", i, expected, problem.Negatives[i].V)
+		}
+	}
+
+	withoutHeader, err := LoadCSV(strings.NewReader(strings.SplitN(data, "\n", 2)[1]), 1)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(withoutHeader.Positives, problem.Positives) ||
+		!reflect.DeepEqual(withoutHeader.Negatives, problem.Negatives) {
+		t.Error("LoadCSV should match LoadCSVWithHeader on the table without its header")
+	}
+	if _, err := LoadCSV(strings.NewReader(data), 1); err == nil {
+		t.Error("expected error for unskipped header")
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	for _, data := range []string{
+		"1,2,3\n1,2\n",
+		"1,a,3\n",
+		"b,2,3\n",
+	} {
+		if _, err := LoadCSV(strings.NewReader(data), 0); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+	if _, err := LoadCSV(strings.NewReader("1,2\n"), 2); err == nil {
+		t.Error("expected error for missing label column")
+	}
+}
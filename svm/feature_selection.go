@@ -0,0 +1,57 @@
+package svm
+
+// RFE selects features using recursive feature elimination (SVM-RFE).
+//
+// Starting with every feature, it repeatedly trains a classifier on the remaining features using
+// the Solver and eliminates the feature with the smallest importance (see FeatureImportances),
+// until only keep features remain.
+// It returns the indices of the remaining features in ascending order, along with a copy of the
+// Problem which only includes those features.
+//
+// Since the importances come from the normal of each classifier, this is only meaningful for
+// linear kernels and standardized features.
+// A new classifier is trained for every eliminated feature, so this can be slow for
+// high-dimensional Problems.
+func RFE(p *Problem, solver Solver, keep int) ([]int, *Problem) {
+	if keep < 1 {
+		panic("must keep at least one feature")
+	}
+	features := make([]int, len(p.Positives[0].V))
+	for i := range features {
+		features[i] = i
+	}
+	reduced := p
+	for len(features) > keep {
+		importances := solver.Solve(reduced).FeatureImportances()
+		worst := 0
+		for i, importance := range importances {
+			if importance < importances[worst] {
+				worst = i
+			}
+		}
+		features = append(features[:worst], features[worst+1:]...)
+		reduced = selectFeatures(p, features)
+	}
+	return features, selectFeatures(p, features)
+}
+
+// selectFeatures creates a copy of a Problem whose samples only include the given features.
+func selectFeatures(p *Problem, features []int) *Problem {
+	project := func(samples []Sample) []Sample {
+		res := make([]Sample, len(samples))
+		for i, sample := range samples {
+			res[i] = Sample{V: make([]float64, len(features)), UserInfo: sample.UserInfo}
+			for j, feature := range features {
+				res[i].V[j] = sample.V[feature]
+			}
+		}
+		return res
+	}
+	return &Problem{
+		Positives:       project(p.Positives),
+		Negatives:       project(p.Negatives),
+		Kernel:          p.Kernel,
+		PositiveWeights: p.PositiveWeights,
+		NegativeWeights: p.NegativeWeights,
+	}
+}
@@ -0,0 +1,42 @@
+package svm
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestRFE(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &Problem{Kernel: LinearKernel}
+	for i := 0; i < 300; i++ {
+		// Features 1, 3, and 4 determine the class, while the rest are pure noise.
+		sample := gaussianSample(rng, 6)
+		if sample.V[1]+2*sample.V[3]-sample.V[4] > 0 {
+			problem.Positives = append(problem.Positives, sample)
+		} else {
+			problem.Negatives = append(problem.Negatives, sample)
+		}
+	}
+	solver := &SubgradientSolver{Tradeoff: 0.01, Steps: 300, StepSize: 0.001}
+
+	features, reduced := RFE(problem, solver, 3)
+	if !reflect.DeepEqual(features, []int{1, 3, 4}) {
+		t.Fatal("unexpected features:", features)
+	}
+	if reduced.sampleCount() != problem.sampleCount() {
+		t.Fatal("reduced problem has the wrong number of samples")
+	}
+	for i := 0; i < problem.sampleCount(); i++ {
+		original, sign := problem.sample(i)
+		projected, projectedSign := reduced.sample(i)
+		expected := []float64{original.V[1], original.V[3], original.V[4]}
+		if sign != projectedSign || !reflect.DeepEqual(projected.V, expected) {
+			t.Fatalf("sample %d was not projected correctly", i)
+		}
+	}
+
+	if features, _ := RFE(problem, solver, 10); len(features) != 6 {
+		t.Error("expected every feature to be kept but got", features)
+	}
+}
@@ -0,0 +1,63 @@
+package svm
+
+// A Float32Classifier is a CombinationClassifier which stores its support vectors and coefficients
+// as float32 values, using roughly half the memory.
+//
+// Decision values are still accumulated as float64 values, so the loss of precision is limited
+// to the rounding of the stored values.
+type Float32Classifier struct {
+	SupportVectors [][]float32
+	Coefficients   []float32
+
+	Threshold float64
+	Kernel    Kernel
+}
+
+// NewFloat32Classifier converts a CombinationClassifier into a Float32Classifier.
+func NewFloat32Classifier(c *CombinationClassifier) *Float32Classifier {
+	res := &Float32Classifier{
+		SupportVectors: make([][]float32, len(c.SupportVectors)),
+		Coefficients:   make([]float32, len(c.Coefficients)),
+		Threshold:      c.Threshold,
+		Kernel:         c.Kernel,
+	}
+	for i, vec := range c.SupportVectors {
+		res.SupportVectors[i] = make([]float32, vec.Dim())
+		vec.ForEachNonZero(func(j int, x float64) {
+			res.SupportVectors[i][j] = float32(x)
+		})
+	}
+	for i, x := range c.Coefficients {
+		res.Coefficients[i] = float32(x)
+	}
+	return res
+}
+
+func (c *Float32Classifier) Classify(sample Sample) bool {
+	return positiveDecision(c.DecisionValue(sample))
+}
+
+// Rating is equivalent to DecisionValue.
+func (c *Float32Classifier) Rating(sample Sample) float64 {
+	return c.DecisionValue(sample)
+}
+
+// DecisionValue computes the weighted sum of kernel products between the support vectors and a
+// sample, plus the Threshold.
+//
+// Each support vector is expanded into a temporary float64 slice before it is passed to the
+// Kernel.
+func (c *Float32Classifier) DecisionValue(sample Sample) float64 {
+	if len(c.SupportVectors) == 0 {
+		return c.Threshold
+	}
+	vec := Sample{V: make([]float64, len(c.SupportVectors[0]))}
+	res := c.Threshold
+	for i, supportVec := range c.SupportVectors {
+		for j, x := range supportVec {
+			vec.V[j] = float64(x)
+		}
+		res += float64(c.Coefficients[i]) * c.Kernel(vec, sample)
+	}
+	return res
+}
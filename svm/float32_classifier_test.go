@@ -0,0 +1,61 @@
+package svm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFloat32ClassifierAgreement(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := ringProblem(100, 0.5, 2)
+	solver := &SMOSolver{Tradeoff: 0.0001}
+	full := solver.Solve(problem)
+	compact := NewFloat32Classifier(full)
+
+	var agreements int
+	const testCount = 1000
+	for i := 0; i < testCount; i++ {
+		sample := Sample{V: []float64{rng.Float64()*5 - 2.5, rng.Float64()*5 - 2.5}}
+		if full.Classify(sample) == compact.Classify(sample) {
+			agreements++
+		}
+	}
+	if rate := float64(agreements) / testCount; rate < 0.99 {
+		t.Error("unexpected agreement rate:", rate)
+	}
+}
+
+func BenchmarkFloat64ClassifierMemory(b *testing.B) {
+	model := largeCombinationClassifier()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res := &CombinationClassifier{
+			SupportVectors: make([]Sample, len(model.SupportVectors)),
+			Coefficients:   append([]float64{}, model.Coefficients...),
+		}
+		for j, vec := range model.SupportVectors {
+			res.SupportVectors[j] = Sample{V: append([]float64{}, vec.V...)}
+		}
+	}
+}
+
+func BenchmarkFloat32ClassifierMemory(b *testing.B) {
+	model := largeCombinationClassifier()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewFloat32Classifier(model)
+	}
+}
+
+// largeCombinationClassifier generates a random model with 10000 support vectors.
+func largeCombinationClassifier() *CombinationClassifier {
+	rng := rand.New(rand.NewSource(1))
+	res := &CombinationClassifier{Kernel: RadialBasisKernel(0.1)}
+	for i := 0; i < 10000; i++ {
+		res.SupportVectors = append(res.SupportVectors, gaussianSample(rng, 50))
+		res.Coefficients = append(res.Coefficients, rng.NormFloat64())
+	}
+	return res
+}
@@ -0,0 +1,100 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// A FourierMap maps samples to random Fourier features, in which the dot product approximates a
+// RadialBasisKernel.
+//
+// Training a linear solver such as SubgradientSolver on the transformed samples yields a
+// non-linear decision boundary in the original sample space, without the cost of a kernelized
+// solver.
+//
+// A FourierMap is safe to use from multiple goroutines.
+type FourierMap struct {
+	gamma   float64
+	offsets []float64
+
+	lock    sync.Mutex
+	rng     *rand.Rand
+	weights [][]float64
+}
+
+// RandomFourierMap creates a FourierMap which approximates RadialBasisKernel(gamma) using dim
+// features.
+// More features give a better approximation.
+//
+// Each feature is sqrt(2/dim)*cos(w*x + b), where w is drawn from a normal distribution with
+// variance 2*gamma and b is drawn uniformly from [0, 2*pi).
+// Since the dimension of w depends on the samples, the weights are drawn from rng the first time
+// a sample is transformed, and every subsequent sample must have the same dimension.
+//
+// This panics if dim is not positive.
+func RandomFourierMap(gamma float64, dim int, rng *rand.Rand) *FourierMap {
+	if dim <= 0 {
+		panic("number of Fourier features must be positive")
+	}
+	res := &FourierMap{
+		gamma:   gamma,
+		offsets: make([]float64, dim),
+		rng:     rng,
+	}
+	for i := range res.offsets {
+		res.offsets[i] = rng.Float64() * 2 * math.Pi
+	}
+	return res
+}
+
+// Transform returns a copy of a Problem with all of its samples mapped to Fourier features.
+// The Kernel of the new Problem is LinearKernel, and the sample weights are preserved.
+func (f *FourierMap) Transform(p *Problem) *Problem {
+	res := &Problem{
+		Positives:       make([]Sample, len(p.Positives)),
+		Negatives:       make([]Sample, len(p.Negatives)),
+		Kernel:          LinearKernel,
+		PositiveWeights: p.PositiveWeights,
+		NegativeWeights: p.NegativeWeights,
+	}
+	for i, sample := range p.Positives {
+		res.Positives[i] = f.TransformSample(sample)
+	}
+	for i, sample := range p.Negatives {
+		res.Negatives[i] = f.TransformSample(sample)
+	}
+	return res
+}
+
+// TransformSample maps a Sample to Fourier features.
+// The UserInfo of the sample is preserved.
+func (f *FourierMap) TransformSample(sample Sample) Sample {
+	weights := f.sampleWeights(len(sample.V))
+	scale := math.Sqrt(2 / float64(len(f.offsets)))
+	res := Sample{V: make([]float64, len(f.offsets)), UserInfo: sample.UserInfo}
+	for i, w := range weights {
+		product := LinearKernel(Sample{V: w}, sample)
+		res.V[i] = scale * math.Cos(product+f.offsets[i])
+	}
+	return res
+}
+
+func (f *FourierMap) sampleWeights(inputDim int) [][]float64 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.weights == nil {
+		stddev := math.Sqrt(2 * f.gamma)
+		f.weights = make([][]float64, len(f.offsets))
+		for i := range f.weights {
+			f.weights[i] = make([]float64, inputDim)
+			for j := range f.weights[i] {
+				f.weights[i][j] = f.rng.NormFloat64() * stddev
+			}
+		}
+		f.rng = nil
+	} else if len(f.weights[0]) != inputDim {
+		panic("samples must be of the sample dimension")
+	}
+	return f.weights
+}
@@ -0,0 +1,61 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFourierMapApproximation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	kernel := RadialBasisKernel(0.5)
+	samples := make([]Sample, 10)
+	for i := range samples {
+		samples[i] = gaussianSample(rng, 3)
+	}
+
+	var lastError float64
+	for i, dim := range []int{10, 100, 10000} {
+		fourierMap := RandomFourierMap(0.5, dim, rng)
+		var meanError float64
+		for _, s1 := range samples {
+			for _, s2 := range samples {
+				approx := LinearKernel(fourierMap.TransformSample(s1),
+					fourierMap.TransformSample(s2))
+				meanError += math.Abs(approx - kernel(s1, s2))
+			}
+		}
+		meanError /= float64(len(samples) * len(samples))
+		if i > 0 && meanError >= lastError {
+			t.Errorf("dim %d gave error %f, previous error was %f", dim, meanError, lastError)
+		}
+		lastError = meanError
+	}
+	if lastError > 0.02 {
+		t.Error("approximation error is too large:", lastError)
+	}
+}
+
+func TestFourierMapSolve(t *testing.T) {
+	problem := ringProblem(50, 0.5, 2)
+	fourierMap := RandomFourierMap(1, 200, rand.New(rand.NewSource(1)))
+	transformed := fourierMap.Transform(problem)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 500, StepSize: 0.01}
+	solution := solver.Solve(transformed)
+	if accuracy := problemAccuracy(solution, transformed); accuracy < 0.95 {
+		t.Error("unexpected accuracy:", accuracy)
+	}
+}
+
+func TestRandomFourierMapInvalidDim(t *testing.T) {
+	for _, dim := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for dimension %d", dim)
+				}
+			}()
+			RandomFourierMap(0.5, dim, rand.New(rand.NewSource(1)))
+		}()
+	}
+}
@@ -30,7 +30,12 @@ type GradientDescentSolver struct {
 	Tradeoff float64
 }
 
+// Solve runs the descent on a Problem.
+// It panics if the Problem has per-sample weights, which GradientDescentSolver does not support.
 func (c *GradientDescentSolver) Solve(p *Problem) *CombinationClassifier {
+	if p.weighted() {
+		panic("GradientDescentSolver does not support sample weights")
+	}
 	sampleCount := float64(len(p.Positives) + len(p.Negatives))
 	maxCoefficient := 1 / (2 * c.Tradeoff * sampleCount)
 	iter := newGradientIterator(p, maxCoefficient)
@@ -0,0 +1,111 @@
+package svm
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// A GridResult is the outcome of cross-validating a single combination of parameters.
+type GridResult struct {
+	Params map[string]float64
+
+	// Scores contains the accuracy on each fold.
+	Scores []float64
+
+	// MeanScore is the mean of Scores.
+	MeanScore float64
+}
+
+// ParameterGrid generates every combination of the given parameter values.
+// For example, {"tradeoff": {0.1, 1}, "gamma": {1, 2}} yields four combinations.
+//
+// The combinations are ordered deterministically, with the alphabetically first parameter
+// varying the slowest.
+func ParameterGrid(values map[string][]float64) []map[string]float64 {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := []map[string]float64{{}}
+	for _, name := range names {
+		var next []map[string]float64
+		for _, partial := range res {
+			for _, value := range values[name] {
+				combination := map[string]float64{name: value}
+				for k, v := range partial {
+					combination[k] = v
+				}
+				next = append(next, combination)
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+// GridSearch cross-validates a Solver for every combination of parameters in a grid, returning the
+// best result along with the results for every combination (in the same order as grid).
+//
+// The factory creates a Solver from a combination of parameters.
+// It is called once per fold, so the Solvers it returns need not be safe to share.
+//
+// Every combination is evaluated on the same folds, which are generated with rng as in
+// CrossValidate.
+// The folds are evaluated concurrently using runtime.GOMAXPROCS(0) goroutines.
+// Ties for the best result go to the combination that appears first in the grid.
+func GridSearch(p *Problem, factory func(params map[string]float64) Solver,
+	grid []map[string]float64, folds int, rng *rand.Rand) (best GridResult, all []GridResult) {
+	foldProblems := crossValidationFolds(p, folds, rng)
+	trainingProblems := make([]*Problem, folds)
+	for i := range foldProblems {
+		trainingProblems[i] = trainingFolds(foldProblems, i)
+	}
+
+	all = make([]GridResult, len(grid))
+	for i, params := range grid {
+		all[i] = GridResult{Params: params, Scores: make([]float64, folds)}
+	}
+
+	type gridTask struct {
+		combination int
+		fold        int
+	}
+	tasks := make(chan gridTask, len(grid)*folds)
+	for i := range grid {
+		for j := 0; j < folds; j++ {
+			tasks <- gridTask{i, j}
+		}
+	}
+	close(tasks)
+
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range tasks {
+				solver := factory(grid[task.combination])
+				classifier := solver.Solve(trainingProblems[task.fold])
+				score := Accuracy(classifier, foldProblems[task.fold])
+				all[task.combination].Scores[task.fold] = score
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range all {
+		var sum float64
+		for _, score := range all[i].Scores {
+			sum += score
+		}
+		all[i].MeanScore = sum / float64(folds)
+		if i == 0 || all[i].MeanScore > best.MeanScore {
+			best = all[i]
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package svm
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParameterGrid(t *testing.T) {
+	actual := ParameterGrid(map[string][]float64{
+		"b": {1, 2},
+		"a": {3, 4},
+	})
+	expected := []map[string]float64{
+		{"a": 3, "b": 1},
+		{"a": 3, "b": 2},
+		{"a": 4, "b": 1},
+		{"a": 4, "b": 2},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestGridSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 30, 3, 0.1)
+	grid := ParameterGrid(map[string][]float64{
+		"steps":    {0, 200},
+		"tradeoff": {0.001, 1000},
+	})
+	factory := func(params map[string]float64) Solver {
+		return &SubgradientSolver{
+			Tradeoff: params["tradeoff"],
+			Steps:    int(params["steps"]),
+			StepSize: 0.0001,
+		}
+	}
+
+	best, all := GridSearch(problem, factory, grid, 3, rng)
+	if len(all) != len(grid) {
+		t.Fatalf("expected %d results but got %d", len(grid), len(all))
+	}
+	for _, result := range all {
+		if len(result.Scores) != 3 {
+			t.Error("expected 3 scores but got", len(result.Scores))
+		}
+		if result.MeanScore > best.MeanScore {
+			t.Errorf("result %v beats best result %v", result, best)
+		}
+	}
+	if best.Params["steps"] != 200 || best.Params["tradeoff"] != 0.001 {
+		t.Error("unexpected best parameters:", best.Params)
+	}
+	if best.MeanScore < 0.9 {
+		t.Error("unexpected best score:", best.MeanScore)
+	}
+}
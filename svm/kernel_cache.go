@@ -0,0 +1,182 @@
+package svm
+
+import (
+	"container/list"
+	"sync"
+)
+
+const float64Size = 8
+
+// An IndexedKernel computes the kernel products of the samples in a fixed list, identified by
+// their indices in the list.
+//
+// Solvers which only need the products of training samples use an IndexedKernel, so that they
+// work the same way whether the products come from a KernelCache or from a precomputed Gram
+// matrix (see Problem.Gram).
+type IndexedKernel interface {
+	// At returns the kernel product of the i-th and j-th samples.
+	At(i, j int) float64
+}
+
+// precomputedKernel is an IndexedKernel backed by a Gram matrix.
+type precomputedKernel [][]float64
+
+func (p precomputedKernel) At(i, j int) float64 {
+	return p[i][j]
+}
+
+// A KernelCache stores the Gram matrix of a Kernel over a fixed list of samples, so that solvers
+// which evaluate the same pairs of samples over and over do not have to recompute them.
+//
+// If the full matrix fits within the cache's memory budget, it is computed up front.
+// Otherwise, rows of the matrix are computed on demand and the least recently used rows are
+// evicted when the budget is exceeded.
+//
+// A KernelCache is safe to use from multiple goroutines.
+type KernelCache struct {
+	kernel  Kernel
+	samples []Sample
+	indices map[int]int
+
+	matrix [][]float64
+
+	lock    sync.Mutex
+	maxRows int
+	rows    map[int]*list.Element
+	lru     *list.List
+	stats   KernelCacheStats
+}
+
+// KernelCacheStats counts how often a KernelCache found the row it needed.
+// Only rows which are computed on demand are counted, so the counts are zero for a KernelCache
+// which stores the entire matrix.
+type KernelCacheStats struct {
+	// Hits is the number of lookups which used a cached row.
+	Hits int
+
+	// Misses is the number of lookups which had to compute a row.
+	Misses int
+}
+
+type kernelCacheRow struct {
+	index  int
+	values []float64
+}
+
+// NewKernelCache creates a KernelCache for the given samples.
+//
+// The maxBytes argument limits how much memory the cache may use for kernel values.
+// If it is 0, the entire matrix is stored regardless of its size.
+// The cache always stores at least one row, even if that row exceeds maxBytes.
+func NewKernelCache(k Kernel, samples []Sample, maxBytes int) *KernelCache {
+	n := len(samples)
+	if maxBytes == 0 || n*n*float64Size <= maxBytes {
+		return newKernelCache(k, samples, 0)
+	}
+	maxRows := maxBytes / (n * float64Size)
+	if maxRows < 1 {
+		maxRows = 1
+	}
+	return newKernelCache(k, samples, maxRows)
+}
+
+// NewKernelCacheRows creates a KernelCache for the given samples which computes rows on demand
+// and stores at most maxRows of them, evicting the least recently used row when it is full.
+//
+// This panics if maxRows is not positive.
+func NewKernelCacheRows(k Kernel, samples []Sample, maxRows int) *KernelCache {
+	if maxRows < 1 {
+		panic("maxRows must be positive")
+	}
+	return newKernelCache(k, samples, maxRows)
+}
+
+// newKernelCache creates a KernelCache which stores up to maxRows rows, or the entire matrix if
+// maxRows is 0.
+func newKernelCache(k Kernel, samples []Sample, maxRows int) *KernelCache {
+	res := &KernelCache{
+		kernel:  k,
+		samples: samples,
+		indices: map[int]int{},
+		maxRows: maxRows,
+	}
+	for i, s := range samples {
+		if s.UserInfo != 0 {
+			res.indices[s.UserInfo] = i
+		}
+	}
+
+	if maxRows == 0 {
+		res.matrix = GramMatrix(k, samples)
+	} else {
+		res.rows = map[int]*list.Element{}
+		res.lru = list.New()
+	}
+
+	return res
+}
+
+// At returns the kernel product of the i-th and j-th samples.
+func (c *KernelCache) At(i, j int) float64 {
+	if c.matrix != nil {
+		return c.matrix[i][j]
+	}
+
+	c.lock.Lock()
+	if elem, ok := c.rows[i]; ok {
+		c.lru.MoveToFront(elem)
+		res := elem.Value.(*kernelCacheRow).values[j]
+		c.stats.Hits++
+		c.lock.Unlock()
+		return res
+	} else if elem, ok := c.rows[j]; ok {
+		c.lru.MoveToFront(elem)
+		res := elem.Value.(*kernelCacheRow).values[i]
+		c.stats.Hits++
+		c.lock.Unlock()
+		return res
+	}
+	c.stats.Misses++
+	c.lock.Unlock()
+
+	row := make([]float64, len(c.samples))
+	for k, s := range c.samples {
+		row[k] = c.kernel(c.samples[i], s)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.rows[i]; !ok {
+		c.rows[i] = c.lru.PushFront(&kernelCacheRow{index: i, values: row})
+		for c.lru.Len() > c.maxRows {
+			oldest := c.lru.Back()
+			c.lru.Remove(oldest)
+			delete(c.rows, oldest.Value.(*kernelCacheRow).index)
+		}
+	}
+	return row[j]
+}
+
+// Stats returns the number of cache hits and misses so far.
+func (c *KernelCache) Stats() KernelCacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stats
+}
+
+// Kernel computes the kernel product of two samples.
+// If both samples are in the cache (as identified by their UserInfo fields), the cached value is
+// used.
+// Otherwise, the value is computed directly using the underlying kernel.
+//
+// This makes it possible to use c.Kernel anywhere a Kernel is expected.
+func (c *KernelCache) Kernel(s1, s2 Sample) float64 {
+	if s1.UserInfo != 0 && s2.UserInfo != 0 {
+		i, ok1 := c.indices[s1.UserInfo]
+		j, ok2 := c.indices[s2.UserInfo]
+		if ok1 && ok2 {
+			return c.At(i, j)
+		}
+	}
+	return c.kernel(s1, s2)
+}
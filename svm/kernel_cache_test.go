@@ -0,0 +1,113 @@
+package svm
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestKernelCacheFull(t *testing.T) {
+	samples := kernelCacheSamples(20)
+	kernel := RadialBasisKernel(0.5)
+	testKernelCache(t, NewKernelCache(kernel, samples, 0), kernel, samples)
+}
+
+func TestKernelCacheLRU(t *testing.T) {
+	samples := kernelCacheSamples(20)
+	kernel := RadialBasisKernel(0.5)
+	cache := NewKernelCache(kernel, samples, 3*20*float64Size)
+	if cache.matrix != nil || cache.maxRows != 3 {
+		t.Fatal("expected an LRU cache with 3 rows")
+	}
+	testKernelCache(t, cache, kernel, samples)
+	if cache.lru.Len() != 3 {
+		t.Error("unexpected number of cached rows:", cache.lru.Len())
+	}
+}
+
+func TestKernelCacheRowsEviction(t *testing.T) {
+	samples := kernelCacheSamples(5)
+	cache := NewKernelCacheRows(RadialBasisKernel(0.5), samples, 2)
+	for _, row := range []int{0, 1, 0, 2} {
+		cache.At(row, row)
+	}
+	if _, ok := cache.rows[1]; ok || len(cache.rows) != 2 {
+		t.Error("row 1 should have been evicted")
+	}
+	if stats := cache.Stats(); stats != (KernelCacheStats{Hits: 1, Misses: 3}) {
+		t.Error("unexpected stats:", stats)
+	}
+
+	// Row 0 was used before row 2, so it is evicted next.
+	cache.At(1, 1)
+	if _, ok := cache.rows[0]; ok {
+		t.Error("row 0 should have been evicted")
+	}
+	for _, row := range []int{1, 2} {
+		if _, ok := cache.rows[row]; !ok {
+			t.Errorf("row %d should be cached", row)
+		}
+	}
+
+	// A lookup can use the row of either sample.
+	cache.At(3, 2)
+	if stats := cache.Stats(); stats != (KernelCacheStats{Hits: 2, Misses: 4}) {
+		t.Error("unexpected stats:", stats)
+	}
+}
+
+func TestKernelCacheConcurrency(t *testing.T) {
+	samples := kernelCacheSamples(20)
+	kernel := RadialBasisKernel(0.5)
+	cache := NewKernelCache(kernel, samples, 2*20*float64Size)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for j := 0; j < 1000; j++ {
+				i1, i2 := rng.Intn(len(samples)), rng.Intn(len(samples))
+				if cache.At(i1, i2) != kernel(samples[i1], samples[i2]) {
+					t.Error("bad value for", i1, i2)
+					return
+				}
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+}
+
+func testKernelCache(t *testing.T, cache *KernelCache, kernel Kernel, samples []Sample) {
+	for pass := 0; pass < 2; pass++ {
+		for i, s1 := range samples {
+			for j, s2 := range samples {
+				expected := kernel(s1, s2)
+				if actual := cache.At(i, j); actual != expected {
+					t.Fatalf("At(%d, %d) should be %f but got %f", i, j, expected, actual)
+				}
+				if actual := cache.Kernel(s1, s2); actual != expected {
+					t.Fatalf("Kernel() for %d, %d should be %f but got %f", i, j, expected,
+						actual)
+				}
+			}
+		}
+	}
+
+	novel := Sample{V: []float64{0.5, 0.5}}
+	actual, expected := cache.Kernel(novel, samples[0]), kernel(novel, samples[0])
+	if actual != expected {
+		t.Errorf("novel product should be %f but got %f", expected, actual)
+	}
+}
+
+func kernelCacheSamples(count int) []Sample {
+	rng := rand.New(rand.NewSource(1))
+	res := make([]Sample, count)
+	for i := range res {
+		res[i] = gaussianSample(rng, 2)
+		res[i].UserInfo = i + 1
+	}
+	return res
+}
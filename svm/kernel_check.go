@@ -0,0 +1,56 @@
+package svm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+	"github.com/unixpickle/num-analysis/linalg/eigen"
+)
+
+// kernelCheckTolerance is the relative error allowed by CheckKernel.
+const kernelCheckTolerance = 1e-9
+
+// CheckKernel verifies that a Kernel behaves like a valid (Mercer) kernel on a list of samples.
+// It is meant as a debugging aid for custom kernels, since invalid kernels cause solvers to
+// misbehave silently.
+//
+// The Gram matrix of the samples must be symmetric, and its smallest eigenvalue must be
+// non-negative, indicating that the matrix is positive semi-definite.
+// Both properties are checked up to a small relative tolerance.
+// The returned error describes the first property which is violated.
+func CheckKernel(k Kernel, samples []Sample) error {
+	n := len(samples)
+	gram := GramMatrix(k, samples)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			x, y := gram[i][j], k(samples[j], samples[i])
+			scale := math.Max(1, math.Max(math.Abs(x), math.Abs(y)))
+			if math.Abs(x-y) > kernelCheckTolerance*scale {
+				return fmt.Errorf("kernel is not symmetric: K(%d, %d) = %g but K(%d, %d) = %g",
+					i, j, x, j, i, y)
+			}
+		}
+	}
+
+	matrix := linalg.NewMatrix(n, n)
+	for i, row := range gram {
+		for j, x := range row {
+			matrix.Set(i, j, x)
+		}
+	}
+	values, _ := eigen.Symmetric(matrix)
+	var minValue, maxMagnitude float64
+	for i, x := range values {
+		if i == 0 || x < minValue {
+			minValue = x
+		}
+		maxMagnitude = math.Max(maxMagnitude, math.Abs(x))
+	}
+	if minValue < -kernelCheckTolerance*math.Max(1, maxMagnitude) {
+		return fmt.Errorf("kernel is not positive semi-definite: Gram matrix has eigenvalue %g",
+			minValue)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package svm
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestCheckKernel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]Sample, 15)
+	for i := range samples {
+		samples[i] = gaussianSample(rng, 3)
+	}
+
+	for name, k := range map[string]Kernel{
+		"linear":     LinearKernel,
+		"rbf":        RadialBasisKernel(0.5),
+		"polynomial": PolynomialKernel(1, 2),
+	} {
+		if err := CheckKernel(k, samples); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+
+	asymmetric := func(s1, s2 Sample) float64 {
+		return s1.V[0] * (s2.V[0] + 1)
+	}
+	if err := CheckKernel(asymmetric, samples); err == nil {
+		t.Error("expected error for asymmetric kernel")
+	} else if !strings.Contains(err.Error(), "symmetric") {
+		t.Error("unexpected error for asymmetric kernel:", err)
+	}
+
+	negative := func(s1, s2 Sample) float64 {
+		return -LinearKernel(s1, s2)
+	}
+	if err := CheckKernel(negative, samples); err == nil {
+		t.Error("expected error for negative kernel")
+	} else if !strings.Contains(err.Error(), "positive semi-definite") {
+		t.Error("unexpected error for negative kernel:", err)
+	}
+}
@@ -0,0 +1,98 @@
+package svm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A KernelConstructor creates a Kernel from a list of numerical parameters.
+type KernelConstructor func(params []float64) (Kernel, error)
+
+var kernelRegistryLock sync.RWMutex
+var kernelRegistry = map[string]KernelConstructor{}
+
+func init() {
+	RegisterKernel("linear", func(params []float64) (Kernel, error) {
+		if len(params) != 0 {
+			return nil, errors.New("linear kernel takes no parameters")
+		}
+		return LinearKernel, nil
+	})
+	RegisterKernel("polynomial", func(params []float64) (Kernel, error) {
+		var degree, coef0, gamma float64
+		switch len(params) {
+		case 2:
+			degree, coef0, gamma = params[1], params[0], 1
+		case 3:
+			degree, coef0, gamma = params[0], params[1], params[2]
+		default:
+			return nil, errors.New("polynomial kernel takes two or three parameters")
+		}
+		if degree < 1 {
+			return nil, errors.New("polynomial degree must be at least 1")
+		} else if degree != math.Trunc(degree) {
+			return nil, errors.New("polynomial degree must be an integer")
+		}
+		return GeneralPolynomialKernel(int(degree), coef0, gamma), nil
+	})
+	RegisterKernel("rbf", func(params []float64) (Kernel, error) {
+		if len(params) != 1 {
+			return nil, errors.New("rbf kernel takes one parameter")
+		}
+		return RadialBasisKernel(params[0]), nil
+	})
+	RegisterKernel("sigmoid", func(params []float64) (Kernel, error) {
+		if len(params) != 2 {
+			return nil, errors.New("sigmoid kernel takes two parameters")
+		}
+		return SigmoidKernel(params[0], params[1]), nil
+	})
+}
+
+// RegisterKernel adds a kernel to the registry used by NamedKernel.
+// Registering a name twice replaces the old constructor.
+//
+// The built-in kernels are "linear", "polynomial" (with parameters b and n as in
+// PolynomialKernel, or degree, coef0, and gamma as in GeneralPolynomialKernel), "rbf" (with
+// parameter coeff), and "sigmoid" (with parameters gamma and coef0).
+func RegisterKernel(name string, c KernelConstructor) {
+	kernelRegistryLock.Lock()
+	defer kernelRegistryLock.Unlock()
+	kernelRegistry[name] = c
+}
+
+// NamedKernel creates a Kernel using a registered constructor.
+//
+// The name consists of the registered name, optionally followed by a colon and a comma-separated
+// list of parameters, such as "linear" or "rbf:0.5".
+func NamedKernel(name string) (Kernel, error) {
+	parts := strings.SplitN(name, ":", 2)
+
+	kernelRegistryLock.RLock()
+	constructor, ok := kernelRegistry[parts[0]]
+	kernelRegistryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown kernel: %s", parts[0])
+	}
+
+	var params []float64
+	if len(parts) == 2 {
+		for _, param := range strings.Split(parts[1], ",") {
+			value, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad parameter for kernel %s: %s", name, param)
+			}
+			params = append(params, value)
+		}
+	}
+
+	k, err := constructor(params)
+	if err != nil {
+		return nil, fmt.Errorf("kernel %s: %s", name, err)
+	}
+	return k, nil
+}
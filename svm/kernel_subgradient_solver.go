@@ -0,0 +1,79 @@
+package svm
+
+// A KernelSubgradientSolver solves Problems using sub-gradient descent in the feature space of the
+// Problem's Kernel.
+//
+// By the representer theorem, the optimal normal is a linear combination of the training samples
+// in feature space, so the decision function has the form sum(beta_i*K(x_i, x)) + threshold.
+// Unlike SubgradientSolver, which looks for a normal in the sample space, this is correct for
+// non-linear kernels.
+//
+// Each step descends along the sub-gradient of the soft-margin function with respect to the normal
+// in feature space, which amounts to adding y_i to beta_i for every sample inside the margin and
+// shrinking every beta_i by the regularization term.
+type KernelSubgradientSolver struct {
+	// Tradeoff has the same meaning as SubgradientSolver's Tradeoff.
+	Tradeoff float64
+
+	// Steps indicates how many descents the solver should make before returning its solution.
+	Steps int
+
+	// StepSize determines how much of the gradient should be added to the current solution at
+	// each step.
+	StepSize float64
+
+	// StepSchedule, if non-nil, is used to compute the step size for each step, overriding
+	// StepSize.
+	StepSchedule StepSchedule
+}
+
+// Solve runs the descent on a Problem.
+// It panics if the Problem has per-sample weights, which KernelSubgradientSolver does not
+// support.
+func (s *KernelSubgradientSolver) Solve(p *Problem) *CombinationClassifier {
+	if p.weighted() {
+		panic("KernelSubgradientSolver does not support sample weights")
+	}
+	samples := make([]Sample, p.sampleCount())
+	signs := make([]float64, p.sampleCount())
+	for i := range samples {
+		samples[i], signs[i] = p.sample(i)
+	}
+	cache := p.indexedKernel(samples)
+
+	coeffs := make([]float64, len(samples))
+	products := make([]float64, len(samples))
+	var threshold float64
+	for step := 0; step < s.Steps; step++ {
+		for i := range products {
+			var product float64
+			for j, coeff := range coeffs {
+				if coeff != 0 {
+					product += coeff * cache.At(i, j)
+				}
+			}
+			products[i] = product
+		}
+
+		stepSize := scheduledStepSize(s.StepSchedule, s.StepSize, step)
+		decay := 1 - 2*s.Tradeoff*stepSize
+		var thresholdChange float64
+		for i, sign := range signs {
+			coeffs[i] *= decay
+			if sign*(products[i]+threshold) < 1 {
+				coeffs[i] += sign * stepSize
+				thresholdChange += sign * stepSize
+			}
+		}
+		threshold += thresholdChange
+	}
+
+	res := &CombinationClassifier{Threshold: threshold, Kernel: p.Kernel}
+	for i, coeff := range coeffs {
+		if coeff != 0 {
+			res.SupportVectors = append(res.SupportVectors, samples[i])
+			res.Coefficients = append(res.Coefficients, coeff)
+		}
+	}
+	return res
+}
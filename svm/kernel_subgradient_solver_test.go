@@ -0,0 +1,24 @@
+package svm
+
+import "testing"
+
+func TestKernelSubgradientSolverRings(t *testing.T) {
+	problem := ringProblem(30, 0.5, 2)
+
+	linear := &SubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+	if accuracy := problemAccuracy(linear.Solve(problem), problem); accuracy > 0.9 {
+		t.Error("SubgradientSolver unexpectedly solved the problem with accuracy", accuracy)
+	}
+
+	kernel := &KernelSubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+	solution := kernel.Solve(problem)
+	if accuracy := problemAccuracy(solution, problem); accuracy != 1 {
+		t.Error("unexpected accuracy:", accuracy)
+	}
+	if !solution.Classify(Sample{V: []float64{0.1, -0.2}}) {
+		t.Error("novel inner sample should be positive")
+	}
+	if solution.Classify(Sample{V: []float64{-1.5, 1.5}}) {
+		t.Error("novel outer sample should be negative")
+	}
+}
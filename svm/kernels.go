@@ -1,9 +1,25 @@
 package svm
 
-import "math"
+import (
+	"math"
+	"reflect"
+	"sync"
+)
 
 // LinearKernel is a Kernel that returns the straight dot product of the two input samples.
+// It panics if the samples have different dimensions, rather than padding the shorter one.
+// Products involving sparse Samples only visit their stored components.
+//
+// Since LinearKernel is itself a Kernel, no constructor is needed: use it directly, as in
+// Problem{Kernel: LinearKernel}.
+// It is registered under the name "linear" for use with NamedKernel.
 func LinearKernel(s1, s2 Sample) float64 {
+	if s1.Sparse != nil || s2.Sparse != nil {
+		if s1.Dim() != s2.Dim() {
+			panic("samples must be of the sample dimension")
+		}
+		return dot(s1, s2)
+	}
 	if len(s1.V) != len(s2.V) {
 		panic("samples must be of the sample dimension")
 	}
@@ -15,15 +31,41 @@ func LinearKernel(s1, s2 Sample) float64 {
 }
 
 // PolynomialKernel generates a Kernel that plugs vectors x and y into the formula (x*y + b)^n.
+// The degree n must be an integer of at least 1.
+// It is equivalent to GeneralPolynomialKernel(int(n), b, 1).
 func PolynomialKernel(b, n float64) Kernel {
+	if n != math.Trunc(n) {
+		panic("polynomial degree must be an integer")
+	}
+	return GeneralPolynomialKernel(int(n), b, 1)
+}
+
+// GeneralPolynomialKernel generates a Kernel that plugs vectors x and y into the formula
+// (gamma*x*y + coef0)^degree, which is the polynomial kernel of libsvm and scikit-learn.
+// The degree must be at least 1.
+func GeneralPolynomialKernel(degree int, coef0, gamma float64) Kernel {
+	if degree < 1 {
+		panic("polynomial degree must be at least 1")
+	}
 	return func(x, y Sample) float64 {
-		return math.Pow(LinearKernel(x, y)+b, n)
+		return math.Pow(gamma*LinearKernel(x, y)+coef0, float64(degree))
 	}
 }
 
 // RadialBasisKernel generates a Kernel that plugs the vectors into exp(-c*||x-y||^2).
+// This is also known as the Gaussian kernel, and coeff is often referred to as gamma.
+// Like LinearKernel, it supports sparse Samples.
 func RadialBasisKernel(coeff float64) Kernel {
 	return func(x, y Sample) float64 {
+		if x.Sparse != nil || y.Sparse != nil {
+			if x.Dim() != y.Dim() {
+				panic("samples must be of the sample dimension")
+			}
+			return math.Exp(-coeff * squaredDistance(x, y))
+		}
+		if len(x.V) != len(y.V) {
+			panic("samples must be of the sample dimension")
+		}
 		var diffSquared float64
 		for i, v := range x.V {
 			diffSquared += math.Pow(v-y.V[i], 2)
@@ -32,26 +74,129 @@ func RadialBasisKernel(coeff float64) Kernel {
 	}
 }
 
+// RBFKernel generates a Kernel that plugs the vectors into exp(-gamma*||x-y||^2).
+// It is another name for RadialBasisKernel, matching the name used by libsvm and scikit-learn.
+// Like RadialBasisKernel, the Kernel panics if the samples have different dimensions.
+func RBFKernel(gamma float64) Kernel {
+	return RadialBasisKernel(gamma)
+}
+
+// SigmoidKernel generates a Kernel that plugs the vectors into tanh(gamma*x*y + coef0).
+//
+// This kernel mimics a two-layer neural network, but it is not positive semi-definite for every
+// choice of gamma and coef0.
+// Solvers which rely on positive semi-definiteness, such as SMOSolver, may behave poorly with it,
+// so it is best paired with SubgradientSolver's numeric gradient.
+func SigmoidKernel(gamma, coef0 float64) Kernel {
+	return func(x, y Sample) float64 {
+		return math.Tanh(gamma*LinearKernel(x, y) + coef0)
+	}
+}
+
+// NormalizedKernel generates a Kernel which normalizes another Kernel, computing
+// base(x, y)/sqrt(base(x, x)*base(y, y)).
+// For LinearKernel, this is the cosine similarity of the two vectors.
+//
+// If either sample has a norm of zero under the base kernel, the result is 0.
+func NormalizedKernel(base Kernel) Kernel {
+	return func(x, y Sample) float64 {
+		norms := base(x, x) * base(y, y)
+		if norms == 0 {
+			return 0
+		}
+		return base(x, y) / math.Sqrt(norms)
+	}
+}
+
+// SumKernels generates a Kernel which adds up the results of other Kernels.
+// The sum of positive semi-definite kernels is positive semi-definite.
+//
+// For example, SumKernels(LinearKernel, RadialBasisKernel(0.1)) combines a linear and a Gaussian
+// kernel.
+func SumKernels(ks ...Kernel) Kernel {
+	return func(x, y Sample) float64 {
+		var sum float64
+		for _, k := range ks {
+			sum += k(x, y)
+		}
+		return sum
+	}
+}
+
+// ProductKernels generates a Kernel which multiplies the results of other Kernels.
+// The product of positive semi-definite kernels is positive semi-definite.
+func ProductKernels(ks ...Kernel) Kernel {
+	return func(x, y Sample) float64 {
+		product := 1.0
+		for _, k := range ks {
+			product *= k(x, y)
+		}
+		return product
+	}
+}
+
 // CachedKernel generates a Kernel which caches results from a different kernel.
 // This requires that each Sample has a unique UserInfo, excepting ones with UserInfo == 0.
 // The caching Kernel will not use the cache for any samples that have UserInfo values of 0.
+//
+// The cache is guarded by a mutex, so the caching Kernel is safe for concurrent use as long as k
+// is.
 func CachedKernel(k Kernel) Kernel {
+	var lock sync.Mutex
 	cache := map[int]map[int]float64{}
 	return func(s1, s2 Sample) float64 {
 		if s1.UserInfo == 0 || s2.UserInfo == 0 {
 			return k(s1, s2)
 		}
+		lock.Lock()
+		val, ok := cache[s1.UserInfo][s2.UserInfo]
+		lock.Unlock()
+		if ok {
+			return val
+		}
+
+		res := k(s1, s2)
+		lock.Lock()
 		s1Cache := cache[s1.UserInfo]
 		if s1Cache == nil {
 			s1Cache = map[int]float64{}
 			cache[s1.UserInfo] = s1Cache
 		}
-		if val, ok := s1Cache[s2.UserInfo]; ok {
-			return val
-		} else {
-			res := k(s1, s2)
-			s1Cache[s2.UserInfo] = res
-			return res
+		s1Cache[s2.UserInfo] = res
+		lock.Unlock()
+		return res
+	}
+}
+
+// GramMatrix computes the Gram matrix of a Kernel over a list of samples, where the entry in row i
+// and column j is k(samples[i], samples[j]).
+//
+// The kernel is assumed to be symmetric, so it is only evaluated for j <= i and the result is
+// mirrored across the diagonal.
+// The matrix takes O(n^2) memory for n samples, so it is only practical for a few thousand
+// samples; see KernelCache for an alternative which computes rows on demand.
+func GramMatrix(k Kernel, samples []Sample) [][]float64 {
+	res := make([][]float64, len(samples))
+	for i, s := range samples {
+		res[i] = make([]float64, len(samples))
+		for j := 0; j <= i; j++ {
+			product := k(s, samples[j])
+			res[i][j] = product
+			res[j][i] = product
 		}
 	}
+	return res
+}
+
+// isLinearKernel returns true if k is LinearKernel itself.
+// Kernels which merely wrap LinearKernel, such as cached kernels, are not detected.
+func isLinearKernel(k Kernel) bool {
+	return sameKernel(k, LinearKernel)
+}
+
+// sameKernel returns true if two Kernels are the same function.
+// Closures created by the same function, such as RadialBasisKernel(1) and RadialBasisKernel(2),
+// cannot be told apart, so they are considered the same.
+func sameKernel(k1, k2 Kernel) bool {
+	return reflect.ValueOf(k1).Pointer() == reflect.ValueOf(k2).Pointer()
 }
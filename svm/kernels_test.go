@@ -0,0 +1,234 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLinearKernel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var kernel Kernel = LinearKernel
+	for i := 0; i < 10; i++ {
+		s1, s2 := gaussianSample(rng, 5), gaussianSample(rng, 5)
+		var expected float64
+		for j, x := range s1.V {
+			expected += x * s2.V[j]
+		}
+		if actual := kernel(s1, s2); math.Abs(actual-expected) > 1e-12 {
+			t.Errorf("expected %f but got %f", expected, actual)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for mismatched dimensions")
+		}
+	}()
+	kernel(Sample{V: []float64{1, 2}}, Sample{V: []float64{1}})
+}
+
+func TestRadialBasisKernel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	kernel := RadialBasisKernel(0.5)
+	for i := 0; i < 10; i++ {
+		s1, s2 := gaussianSample(rng, 5), gaussianSample(rng, 5)
+		if kernel(s1, s2) != kernel(s2, s1) {
+			t.Error("kernel is not symmetric")
+		}
+		if kernel(s1, s1) != 1 {
+			t.Error("kernel of sample with itself should be 1 but got", kernel(s1, s1))
+		}
+	}
+
+	actual := kernel(Sample{V: []float64{1, 2}}, Sample{V: []float64{2, 0}})
+	if expected := math.Exp(-0.5 * 5); math.Abs(actual-expected) > 1e-12 {
+		t.Errorf("expected %f but got %f", expected, actual)
+	}
+}
+
+func TestRBFKernel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	kernel, expected := RBFKernel(0.5), RadialBasisKernel(0.5)
+	for i := 0; i < 10; i++ {
+		s1, s2 := gaussianSample(rng, 5), gaussianSample(rng, 5)
+		if kernel(s1, s2) != kernel(s2, s1) {
+			t.Error("kernel is not symmetric")
+		}
+		if kernel(s1, s1) != 1 {
+			t.Error("kernel of sample with itself should be 1 but got", kernel(s1, s1))
+		}
+		if kernel(s1, s2) != expected(s1, s2) {
+			t.Errorf("expected %f but got %f", expected(s1, s2), kernel(s1, s2))
+		}
+	}
+	if !sameKernel(kernel, expected) {
+		t.Error("RBFKernel should be interchangeable with RadialBasisKernel")
+	}
+}
+
+func TestRadialBasisKernelDimensions(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for mismatched dimensions")
+		}
+	}()
+	RadialBasisKernel(1)(Sample{V: []float64{1, 2}}, Sample{V: []float64{1, 2, 3}})
+}
+
+func TestPolynomialKernelLinear(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	kernel := PolynomialKernel(0, 1)
+	general := GeneralPolynomialKernel(1, 0, 1)
+	for i := 0; i < 10; i++ {
+		s1, s2 := gaussianSample(rng, 5), gaussianSample(rng, 5)
+		if actual, expected := kernel(s1, s2), LinearKernel(s1, s2); actual != expected {
+			t.Errorf("expected %f but got %f", expected, actual)
+		}
+		if actual, expected := general(s1, s2), LinearKernel(s1, s2); actual != expected {
+			t.Errorf("expected %f but got %f", expected, actual)
+		}
+	}
+}
+
+func TestPolynomialKernel(t *testing.T) {
+	actual := PolynomialKernel(1, 3)(Sample{V: []float64{1, 2}}, Sample{V: []float64{3, -1}})
+	if actual != 8 {
+		t.Error("expected 8 but got", actual)
+	}
+
+	s1, s2 := Sample{V: []float64{1, 2}}, Sample{V: []float64{3, -1}}
+	if actual := GeneralPolynomialKernel(2, 2, 0.5)(s1, s2); actual != 6.25 {
+		t.Error("expected 6.25 but got", actual)
+	}
+	if actual := GeneralPolynomialKernel(3, -3, 1)(s1, s2); actual != -8 {
+		t.Error("expected -8 but got", actual)
+	}
+}
+
+func TestPolynomialKernelDegree(t *testing.T) {
+	for name, construct := range map[string]func(){
+		"degree 0":         func() { PolynomialKernel(1, 0) },
+		"degree 2.5":       func() { PolynomialKernel(1, 2.5) },
+		"general degree 0": func() { GeneralPolynomialKernel(0, 1, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %s", name)
+				}
+			}()
+			construct()
+		}()
+	}
+}
+
+func TestSigmoidKernel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	kernel := SigmoidKernel(0.5, -1)
+	for i := 0; i < 10; i++ {
+		s1, s2 := gaussianSample(rng, 5), gaussianSample(rng, 5)
+		if kernel(s1, s2) != kernel(s2, s1) {
+			t.Error("kernel is not symmetric")
+		}
+	}
+
+	s1, s2 := Sample{V: []float64{100, 100}}, Sample{V: []float64{100, -50}}
+	if actual := kernel(s1, s1); math.Abs(actual-1) > 1e-12 {
+		t.Error("expected saturation at 1 but got", actual)
+	}
+	if actual := kernel(s1, Sample{V: []float64{-100, -100}}); math.Abs(actual+1) > 1e-12 {
+		t.Error("expected saturation at -1 but got", actual)
+	}
+	if actual, expected := kernel(s1, s2), math.Tanh(0.5*5000-1); actual != expected {
+		t.Errorf("expected %f but got %f", expected, actual)
+	}
+}
+
+func TestNormalizedKernel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	kernel := NormalizedKernel(LinearKernel)
+	for i := 0; i < 10; i++ {
+		s1, s2 := gaussianSample(rng, 5), gaussianSample(rng, 5)
+		if actual := kernel(s1, s1); math.Abs(actual-1) > 1e-12 {
+			t.Error("kernel of sample with itself should be 1 but got", actual)
+		}
+
+		scaled := Sample{V: make([]float64, len(s2.V))}
+		for j, x := range s2.V {
+			scaled.V[j] = x * 3.5
+		}
+		expected, actual := kernel(s1, s2), kernel(s1, scaled)
+		if math.Abs(actual-expected) > 1e-12 {
+			t.Errorf("scaling changed similarity from %f to %f", expected, actual)
+		}
+	}
+
+	if actual := kernel(Sample{V: []float64{0, 0}}, Sample{V: []float64{1, 2}}); actual != 0 {
+		t.Error("expected 0 for zero sample but got", actual)
+	}
+}
+
+func TestKernelCombinators(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	k1, k2, k3 := LinearKernel, RadialBasisKernel(0.1), PolynomialKernel(1, 2)
+	combinators := map[string]func(ks ...Kernel) Kernel{
+		"sum":     SumKernels,
+		"product": ProductKernels,
+	}
+	for i := 0; i < 10; i++ {
+		s1, s2 := gaussianSample(rng, 3), gaussianSample(rng, 3)
+		for name, combine := range combinators {
+			left := combine(combine(k1, k2), k3)(s1, s2)
+			right := combine(k1, combine(k2, k3))(s1, s2)
+			if math.Abs(left-right) > 1e-9*math.Max(1, math.Abs(left)) {
+				t.Errorf("%s: %f is not %f", name, left, right)
+			}
+		}
+
+		expected := 2 * LinearKernel(s1, s2)
+		if actual := SumKernels(LinearKernel, LinearKernel)(s1, s2); actual != expected {
+			t.Errorf("expected %f but got %f", expected, actual)
+		}
+	}
+}
+
+func TestGramMatrix(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]Sample, 6)
+	for i := range samples {
+		samples[i] = gaussianSample(rng, 3)
+	}
+	kernel := RadialBasisKernel(0.5)
+	gram := GramMatrix(kernel, samples)
+	if len(gram) != len(samples) {
+		t.Fatal("unexpected number of rows:", len(gram))
+	}
+	for i, row := range gram {
+		if len(row) != len(samples) {
+			t.Fatalf("row %d has %d entries", i, len(row))
+		}
+		for j, x := range row {
+			if x != gram[j][i] {
+				t.Errorf("entry %d,%d is %f but entry %d,%d is %f", i, j, x, j, i, gram[j][i])
+			}
+		}
+	}
+	for _, pair := range [][2]int{{0, 0}, {1, 4}, {5, 2}, {3, 3}} {
+		i, j := pair[0], pair[1]
+		if expected := kernel(samples[i], samples[j]); gram[i][j] != expected {
+			t.Errorf("entry %d,%d should be %f but got %f", i, j, expected, gram[i][j])
+		}
+	}
+	if len(GramMatrix(kernel, nil)) != 0 {
+		t.Error("expected empty matrix")
+	}
+}
+
+func gaussianSample(rng *rand.Rand, dim int) Sample {
+	res := make([]float64, dim)
+	for i := range res {
+		res[i] = rng.NormFloat64()
+	}
+	return Sample{V: res}
+}
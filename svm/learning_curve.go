@@ -0,0 +1,77 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+)
+
+// learningCurveValidationFraction is the fraction of each class held out by LearningCurve.
+const learningCurveValidationFraction = 0.25
+
+// A CurvePoint is a point on a learning curve.
+type CurvePoint struct {
+	// Fraction is the fraction of the training samples used at this point.
+	Fraction float64
+
+	// TrainingSize is the number of samples the classifier was trained on.
+	TrainingSize int
+
+	// TrainingAccuracy is the accuracy of the classifier on the samples it was trained on.
+	TrainingAccuracy float64
+
+	// ValidationAccuracy is the accuracy of the classifier on the held-out samples.
+	ValidationAccuracy float64
+}
+
+// LearningCurve trains classifiers on increasingly large subsets of a Problem and measures their
+// accuracy on the training subsets and on held-out samples.
+//
+// A quarter of each class is held out for validation using Split, and the rest is used for
+// training.
+// For each of the fractions, a classifier is trained on that fraction of each class of the
+// training samples.
+// The subsets are nested, so that a larger fraction always includes the samples of a smaller
+// one, and every subset includes at least one sample from each class.
+//
+// If the validation accuracy is still rising at the largest fraction, more data would likely
+// help.
+// If the training and validation accuracies have converged to a low value, the model is too
+// simple, and more data will not help.
+func LearningCurve(p *Problem, solver Solver, fractions []float64,
+	rng *rand.Rand) []CurvePoint {
+	training, validation := p.Split(learningCurveValidationFraction, rng)
+	positiveOrder := rng.Perm(len(training.Positives))
+	negativeOrder := rng.Perm(len(training.Negatives))
+	weighted := training.weighted()
+
+	res := make([]CurvePoint, len(fractions))
+	for i, fraction := range fractions {
+		subset := &Problem{Kernel: p.Kernel}
+		for _, j := range positiveOrder[:subsetSize(fraction, len(positiveOrder))] {
+			subset.appendSample(training, j, weighted)
+		}
+		for _, j := range negativeOrder[:subsetSize(fraction, len(negativeOrder))] {
+			subset.appendSample(training, j+len(training.Positives), weighted)
+		}
+		classifier := solver.Solve(subset)
+		res[i] = CurvePoint{
+			Fraction:           fraction,
+			TrainingSize:       subset.sampleCount(),
+			TrainingAccuracy:   Accuracy(classifier, subset),
+			ValidationAccuracy: Accuracy(classifier, validation),
+		}
+	}
+	return res
+}
+
+// subsetSize computes the number of samples in the given fraction of count samples, which is
+// always at least 1 and at most count.
+func subsetSize(fraction float64, count int) int {
+	size := int(math.Round(fraction * float64(count)))
+	if size < 1 {
+		return 1
+	} else if size > count {
+		return count
+	}
+	return size
+}
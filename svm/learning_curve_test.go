@@ -0,0 +1,35 @@
+package svm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLearningCurve(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 200, 3, 0.05)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+	fractions := []float64{0.001, 0.1, 0.5, 1}
+	points := LearningCurve(problem, solver, fractions, rng)
+
+	if len(points) != len(fractions) {
+		t.Fatalf("expected %d points but got %d", len(fractions), len(points))
+	}
+	for i, point := range points {
+		if point.Fraction != fractions[i] {
+			t.Errorf("point %d: expected fraction %f but got %f", i, fractions[i],
+				point.Fraction)
+		}
+		if i > 0 && point.TrainingSize <= points[i-1].TrainingSize {
+			t.Errorf("point %d: training size %d did not increase", i, point.TrainingSize)
+		}
+	}
+	if first := points[0].TrainingSize; first != 2 {
+		t.Error("expected one sample per class at the smallest fraction but got", first)
+	}
+	if last := points[len(points)-1]; last.TrainingSize != 300 {
+		t.Error("expected 300 training samples at the largest fraction but got", last.TrainingSize)
+	} else if last.ValidationAccuracy < 0.85 {
+		t.Error("bad validation accuracy:", last.ValidationAccuracy)
+	}
+}
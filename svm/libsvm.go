@@ -0,0 +1,97 @@
+package svm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LoadLIBSVM reads a Problem from the sparse format used by LIBSVM, in which every line has the
+// form "label index:value index:value ...".
+//
+// Labels of +1 are positive, while labels of -1 or 0 are negative.
+// Indices start at 1, and components which are not listed are zero.
+// Every Sample is padded to the largest index that appears in the data.
+// Blank lines are ignored, as is anything following a '#'.
+//
+// The Kernel of the resulting Problem is LinearKernel.
+func LoadLIBSVM(r io.Reader) (*Problem, error) {
+	type sparseEntry struct {
+		index int
+		value float64
+	}
+	type sparseLine struct {
+		positive bool
+		entries  []sparseEntry
+	}
+
+	var lines []sparseLine
+	var dim int
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		text := scanner.Text()
+		if idx := strings.IndexByte(text, '#'); idx >= 0 {
+			text = text[:idx]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var line sparseLine
+		label, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad label: %s", lineNum, fields[0])
+		}
+		switch label {
+		case 1:
+			line.positive = true
+		case -1, 0:
+			line.positive = false
+		default:
+			return nil, fmt.Errorf("line %d: unsupported label: %s", lineNum, fields[0])
+		}
+
+		for _, field := range fields[1:] {
+			parts := strings.SplitN(field, ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("line %d: bad feature: %s", lineNum, field)
+			}
+			index, err := strconv.Atoi(parts[0])
+			if err != nil || index < 1 {
+				return nil, fmt.Errorf("line %d: bad index: %s", lineNum, parts[0])
+			}
+			value, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad value: %s", lineNum, parts[1])
+			}
+			line.entries = append(line.entries, sparseEntry{index, value})
+			if index > dim {
+				dim = index
+			}
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	res := &Problem{Kernel: LinearKernel}
+	for _, line := range lines {
+		sample := Sample{V: make([]float64, dim)}
+		for _, entry := range line.entries {
+			sample.V[entry.index-1] = entry.value
+		}
+		if line.positive {
+			res.Positives = append(res.Positives, sample)
+		} else {
+			res.Negatives = append(res.Negatives, sample)
+		}
+	}
+	return res, nil
+}
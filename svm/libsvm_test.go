@@ -0,0 +1,56 @@
+package svm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadLIBSVM(t *testing.T) {
+	data := "+1 1:0.5 3:2\n" +
+		"-1 2:-1\n" +
+		"\n" +
+		"0 4:1.5 # comment\n" +
+		"1 2:3 4:-2\n"
+	problem, err := LoadLIBSVM(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPositives := [][]float64{{0.5, 0, 2, 0}, {0, 3, 0, -2}}
+	expectedNegatives := [][]float64{{0, -1, 0, 0}, {0, 0, 0, 1.5}}
+	if len(problem.Positives) != len(expectedPositives) {
+		t.Fatal("unexpected positive count:", len(problem.Positives))
+	}
+	if len(problem.Negatives) != len(expectedNegatives) {
+		t.Fatal("unexpected negative count:", len(problem.Negatives))
+	}
+	for i, expected := range expectedPositives {
+		if !reflect.DeepEqual(problem.Positives[i].V, expected) {
+			t.Errorf("positive %d should be %v but got %v", i, expected, problem.Positives[i].V)
+		}
+	}
+	for i, expected := range expectedNegatives {
+		if !reflect.DeepEqual(problem.Negatives[i].V, expected) {
+			t.Errorf("negative %d should be %v but got %v", i, expected, problem.Negatives[i].V)
+		}
+	}
+	if problem.Kernel == nil {
+		t.Error("missing kernel")
+	}
+}
+
+func TestLoadLIBSVMErrors(t *testing.T) {
+	for _, data := range []string{
+		"x 1:2\n",
+		"2 1:2\n",
+		"1 0:2\n",
+		"1 a:2\n",
+		"1 1:b\n",
+		"1 1\n",
+	} {
+		if _, err := LoadLIBSVM(strings.NewReader(data)); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
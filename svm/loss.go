@@ -0,0 +1,94 @@
+package svm
+
+import "math"
+
+// A Loss measures the error of a sample given its margin, which is the product of its sign
+// (1 for positives, -1 for negatives) and the classifier's decision value for it.
+//
+// A SubgradientSolver minimizes the weighted sum of the losses of the samples plus the
+// regularization penalty.
+// Losses should be convex in the margin for the solution to be optimal.
+type Loss interface {
+	// Value computes the loss for a margin.
+	Value(margin float64) float64
+
+	// Gradient computes the (sub-)derivative of the loss with respect to the margin.
+	Gradient(margin float64) float64
+}
+
+// HingeLoss is the standard soft-margin loss, max(0, 1-margin).
+type HingeLoss struct{}
+
+func (HingeLoss) Value(margin float64) float64 {
+	return math.Max(0, 1-margin)
+}
+
+func (HingeLoss) Gradient(margin float64) float64 {
+	if margin >= 1 {
+		return 0
+	}
+	return -1
+}
+
+// SquaredHingeLoss is the square of the hinge loss, max(0, 1-margin)^2, as used by the "L2-SVM".
+//
+// It is differentiable everywhere, so the gradient is exact rather than a sub-gradient.
+type SquaredHingeLoss struct{}
+
+func (SquaredHingeLoss) Value(margin float64) float64 {
+	loss := math.Max(0, 1-margin)
+	return loss * loss
+}
+
+func (SquaredHingeLoss) Gradient(margin float64) float64 {
+	return -2 * math.Max(0, 1-margin)
+}
+
+// SmoothHingeLoss is a Huber-style hinge loss, which replaces the kink at a margin of 1 with a
+// quadratic.
+//
+// The loss is (1-m)^2/(2*Width) for m in [1-Width, 1] and 1-m-Width/2 below that, so that its
+// derivative is continuous.
+// If Width is zero, this is equivalent to HingeLoss.
+type SmoothHingeLoss struct {
+	Width float64
+}
+
+func (s SmoothHingeLoss) Value(margin float64) float64 {
+	loss := math.Max(0, 1-margin)
+	if s.Width == 0 {
+		return loss
+	} else if loss < s.Width {
+		return loss * loss / (2 * s.Width)
+	}
+	return loss - s.Width/2
+}
+
+func (s SmoothHingeLoss) Gradient(margin float64) float64 {
+	if margin >= 1 {
+		return 0
+	} else if 1-margin < s.Width {
+		return (margin - 1) / s.Width
+	}
+	return -1
+}
+
+// LogisticLoss is the loss of logistic regression, log(1+exp(-margin)).
+//
+// Unlike the hinge losses, it is never zero, so every sample contributes to the gradient.
+type LogisticLoss struct{}
+
+func (LogisticLoss) Value(margin float64) float64 {
+	if margin > 0 {
+		return math.Log1p(math.Exp(-margin))
+	}
+	return math.Log1p(math.Exp(margin)) - margin
+}
+
+func (LogisticLoss) Gradient(margin float64) float64 {
+	if margin > 0 {
+		exp := math.Exp(-margin)
+		return -exp / (1 + exp)
+	}
+	return -1 / (1 + math.Exp(margin))
+}
@@ -0,0 +1,72 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLossGradients(t *testing.T) {
+	losses := map[string]Loss{
+		"hinge":         HingeLoss{},
+		"squared hinge": SquaredHingeLoss{},
+		"smooth hinge":  SmoothHingeLoss{Width: 0.5},
+		"logistic":      LogisticLoss{},
+	}
+	for name, loss := range losses {
+		for _, margin := range []float64{-50, -2, -0.3, 0.3, 0.7, 0.95, 1.5, 50} {
+			numeric := (loss.Value(margin+1e-6) - loss.Value(margin-1e-6)) / 2e-6
+			if actual := loss.Gradient(margin); math.Abs(actual-numeric) > 1e-6 {
+				t.Errorf("%s: gradient at %f should be %f but got %f", name, margin, numeric,
+					actual)
+			}
+		}
+		if math.IsInf(loss.Value(-1000), 0) || loss.Value(1000) > 1e-9 {
+			t.Errorf("%s: bad loss for extreme margins", name)
+		}
+	}
+}
+
+func TestSubgradientLoss(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.2)
+	for _, loss := range []Loss{HingeLoss{}, SquaredHingeLoss{}, LogisticLoss{}} {
+		solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 500, StepSize: 0.001, Loss: loss}
+		if accuracy := problemAccuracy(solver.Solve(problem), problem); accuracy != 1 {
+			t.Errorf("%T: unexpected accuracy %f", loss, accuracy)
+		}
+	}
+
+	defaultSolver := &SubgradientSolver{Tradeoff: 0.01, Steps: 100, StepSize: 0.001}
+	hingeSolver := *defaultSolver
+	hingeSolver.Loss = HingeLoss{}
+	expected, actual := defaultSolver.Solve(problem), hingeSolver.Solve(problem)
+	if actual.Threshold != expected.Threshold {
+		t.Errorf("threshold should be %f but got %f", expected.Threshold, actual.Threshold)
+	}
+	for i, x := range expected.HyperplaneNormal.V {
+		if actual.HyperplaneNormal.V[i] != x {
+			t.Errorf("component %d should be %f but got %f", i, x, actual.HyperplaneNormal.V[i])
+		}
+	}
+
+	args := randomSoftMarginArgs(rng, 3)
+	var hinge float64
+	for i := 0; i < problem.sampleCount(); i++ {
+		sample, sign := problem.sample(i)
+		hinge += math.Max(0, 1-sign*(LinearKernel(Sample{V: args.normal}, sample)+args.threshold))
+	}
+	hinge += defaultSolver.Tradeoff * LinearKernel(Sample{V: args.normal}, Sample{V: args.normal})
+	if objective := defaultSolver.softMarginFunction(problem, args); objective != hinge {
+		t.Errorf("objective should be %f but got %f", hinge, objective)
+	}
+
+	logistic := &SubgradientSolver{Tradeoff: 0.1, Loss: LogisticLoss{}}
+	analytic := logistic.analyticGradient(problem, args)
+	numeric := logistic.referenceNumericGradient(problem, args)
+	for i, x := range numeric.normal {
+		if math.Abs(analytic.normal[i]-x) > 1e-4 {
+			t.Errorf("partial %d should be %f but got %f", i, x, analytic.normal[i])
+		}
+	}
+}
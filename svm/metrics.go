@@ -0,0 +1,137 @@
+package svm
+
+import (
+	"fmt"
+	"math"
+)
+
+// A ConfusionMatrix counts the correct and incorrect classifications made by a Classifier,
+// treating positives as the target class.
+type ConfusionMatrix struct {
+	TruePositives  int
+	FalsePositives int
+	TrueNegatives  int
+	FalseNegatives int
+}
+
+// Evaluate computes the ConfusionMatrix of a Classifier on a Problem.
+func Evaluate(c Classifier, p *Problem) ConfusionMatrix {
+	var res ConfusionMatrix
+	for _, sample := range p.Positives {
+		if c.Classify(sample) {
+			res.TruePositives++
+		} else {
+			res.FalseNegatives++
+		}
+	}
+	for _, sample := range p.Negatives {
+		if c.Classify(sample) {
+			res.FalsePositives++
+		} else {
+			res.TrueNegatives++
+		}
+	}
+	return res
+}
+
+// Accuracy returns the fraction of samples which were classified correctly.
+func (c ConfusionMatrix) Accuracy() float64 {
+	return safeRatio(c.TruePositives+c.TrueNegatives,
+		c.TruePositives+c.FalsePositives+c.TrueNegatives+c.FalseNegatives)
+}
+
+// Precision returns the fraction of the samples classified as positive which are actually
+// positive.
+// If no samples were classified as positive, the precision is 0.
+func (c ConfusionMatrix) Precision() float64 {
+	return safeRatio(c.TruePositives, c.TruePositives+c.FalsePositives)
+}
+
+// Recall returns the fraction of the positive samples which were classified as positive.
+// If there were no positive samples, the recall is 0.
+func (c ConfusionMatrix) Recall() float64 {
+	return safeRatio(c.TruePositives, c.TruePositives+c.FalseNegatives)
+}
+
+// F1Score returns the harmonic mean of the precision and recall.
+// If both the precision and recall are 0, the score is 0.
+func (c ConfusionMatrix) F1Score() float64 {
+	return safeRatio(2*c.TruePositives, 2*c.TruePositives+c.FalsePositives+c.FalseNegatives)
+}
+
+// Specificity returns the fraction of the negative samples which were classified as negative.
+// If there were no negative samples, the specificity is 0.
+func (c ConfusionMatrix) Specificity() float64 {
+	return safeRatio(c.TrueNegatives, c.TrueNegatives+c.FalsePositives)
+}
+
+// BalancedAccuracy returns the mean of the recall and the specificity, which is the accuracy
+// weighted so that both classes count equally.
+// Unlike Accuracy, this is 0.5 for a classifier which always picks the majority class.
+func (c ConfusionMatrix) BalancedAccuracy() float64 {
+	return (c.Recall() + c.Specificity()) / 2
+}
+
+// MatthewsCorrelation returns the Matthews correlation coefficient (MCC), the correlation between
+// the true and predicted classes.
+// It ranges from -1 (every sample misclassified) through 0 (no better than chance) to 1 (every
+// sample classified correctly), and is robust to class imbalance.
+// If every sample is in the same true or predicted class, the correlation is undefined and 0 is
+// returned, since such a classification tells nothing about the classes.
+func (c ConfusionMatrix) MatthewsCorrelation() float64 {
+	tp, fp := float64(c.TruePositives), float64(c.FalsePositives)
+	tn, fn := float64(c.TrueNegatives), float64(c.FalseNegatives)
+	denom := (tp + fp) * (tp + fn) * (tn + fp) * (tn + fn)
+	if denom == 0 {
+		return 0
+	}
+	return (tp*tn - fp*fn) / math.Sqrt(denom)
+}
+
+func (c ConfusionMatrix) String() string {
+	return fmt.Sprintf("ConfusionMatrix{TP: %d, FP: %d, TN: %d, FN: %d}", c.TruePositives,
+		c.FalsePositives, c.TrueNegatives, c.FalseNegatives)
+}
+
+// Accuracy computes the fraction of a Problem's samples which a Classifier classifies correctly.
+func Accuracy(c Classifier, p *Problem) float64 {
+	return Evaluate(c, p).Accuracy()
+}
+
+// Precision computes the fraction of the samples classified as positive which are actually
+// positive.
+// If no samples are classified as positive, the precision is 0.
+func Precision(c Classifier, p *Problem) float64 {
+	return Evaluate(c, p).Precision()
+}
+
+// Recall computes the fraction of the positive samples which are classified as positive.
+// If there are no positive samples, the recall is 0.
+func Recall(c Classifier, p *Problem) float64 {
+	return Evaluate(c, p).Recall()
+}
+
+// F1Score computes the harmonic mean of the precision and recall.
+// If both the precision and recall are 0, the score is 0.
+func F1Score(c Classifier, p *Problem) float64 {
+	return Evaluate(c, p).F1Score()
+}
+
+// BalancedAccuracy computes the mean of the recall and the specificity.
+// See ConfusionMatrix.BalancedAccuracy.
+func BalancedAccuracy(c Classifier, p *Problem) float64 {
+	return Evaluate(c, p).BalancedAccuracy()
+}
+
+// MatthewsCorrelation computes the Matthews correlation coefficient of the classifications.
+// See ConfusionMatrix.MatthewsCorrelation.
+func MatthewsCorrelation(c Classifier, p *Problem) float64 {
+	return Evaluate(c, p).MatthewsCorrelation()
+}
+
+func safeRatio(num, denom int) float64 {
+	if denom == 0 {
+		return 0
+	}
+	return float64(num) / float64(denom)
+}
@@ -0,0 +1,117 @@
+package svm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	classifier, problem := confusionProblem()
+
+	// 3 true positives, 2 false negatives, 1 false positive, and 4 true negatives.
+	metrics := []struct {
+		name     string
+		metric   func(Classifier, *Problem) float64
+		expected float64
+	}{
+		{"accuracy", Accuracy, 7.0 / 10.0},
+		{"precision", Precision, 3.0 / 4.0},
+		{"recall", Recall, 3.0 / 5.0},
+		{"f1", F1Score, 2.0 / 3.0},
+		{"balanced accuracy", BalancedAccuracy, 7.0 / 10.0},
+		{"mcc", MatthewsCorrelation, 10 / math.Sqrt(600)},
+	}
+	for _, m := range metrics {
+		if actual := m.metric(classifier, problem); math.Abs(actual-m.expected) > 1e-12 {
+			t.Errorf("%s should be %f but got %f", m.name, m.expected, actual)
+		}
+	}
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	classifier, problem := confusionProblem()
+	matrix := Evaluate(classifier, problem)
+	expected := ConfusionMatrix{
+		TruePositives:  3,
+		FalsePositives: 1,
+		TrueNegatives:  4,
+		FalseNegatives: 2,
+	}
+	if matrix != expected {
+		t.Fatal("unexpected matrix:", matrix)
+	}
+
+	derived := []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{"accuracy", matrix.Accuracy(), Accuracy(classifier, problem)},
+		{"precision", matrix.Precision(), Precision(classifier, problem)},
+		{"recall", matrix.Recall(), Recall(classifier, problem)},
+		{"f1", matrix.F1Score(), F1Score(classifier, problem)},
+		{"specificity", matrix.Specificity(), 4.0 / 5.0},
+		{"balanced accuracy", matrix.BalancedAccuracy(), BalancedAccuracy(classifier, problem)},
+		{"mcc", matrix.MatthewsCorrelation(), MatthewsCorrelation(classifier, problem)},
+	}
+	for _, d := range derived {
+		if math.Abs(d.actual-d.expected) > 1e-12 {
+			t.Errorf("%s should be %f but got %f", d.name, d.expected, d.actual)
+		}
+	}
+
+	if s := matrix.String(); s != "ConfusionMatrix{TP: 3, FP: 1, TN: 4, FN: 2}" {
+		t.Error("unexpected string:", s)
+	}
+}
+
+func TestMetricsNoPredictedPositives(t *testing.T) {
+	classifier, problem := confusionProblem()
+	classifier.Threshold = -100
+	if actual := Precision(classifier, problem); actual != 0 {
+		t.Error("expected precision 0 but got", actual)
+	}
+	if actual := F1Score(classifier, problem); actual != 0 {
+		t.Error("expected F1 score 0 but got", actual)
+	}
+}
+
+func TestMetricsImbalanced(t *testing.T) {
+	// A classifier which labels everything negative on a Problem with 1 positive and 9 negatives.
+	majority := ConfusionMatrix{TrueNegatives: 9, FalseNegatives: 1}
+	if actual := majority.Accuracy(); actual != 0.9 {
+		t.Error("expected accuracy 0.9 but got", actual)
+	}
+	if actual := majority.BalancedAccuracy(); actual != 0.5 {
+		t.Error("expected balanced accuracy 0.5 but got", actual)
+	}
+	if actual := majority.MatthewsCorrelation(); actual != 0 {
+		t.Error("expected MCC 0 but got", actual)
+	}
+
+	perfect := ConfusionMatrix{TruePositives: 1, TrueNegatives: 9}
+	inverted := ConfusionMatrix{FalsePositives: 9, FalseNegatives: 1}
+	if actual := perfect.MatthewsCorrelation(); math.Abs(actual-1) > 1e-12 {
+		t.Error("expected MCC 1 but got", actual)
+	}
+	if actual := inverted.MatthewsCorrelation(); math.Abs(actual+1) > 1e-12 {
+		t.Error("expected MCC -1 but got", actual)
+	}
+}
+
+// confusionProblem generates a one-dimensional Problem and a LinearClassifier which produces
+// 3 true positives, 2 false negatives, 1 false positive, and 4 true negatives on it.
+func confusionProblem() (*LinearClassifier, *Problem) {
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{1}},
+		Kernel:           LinearKernel,
+	}
+	problem := &Problem{Kernel: LinearKernel}
+	for _, x := range []float64{1, 2, 3, -1, -2} {
+		problem.Positives = append(problem.Positives, Sample{V: []float64{x}})
+	}
+	for _, x := range []float64{0.5, -1, -2, -3, -4} {
+		problem.Negatives = append(problem.Negatives, Sample{V: []float64{x}})
+	}
+	return classifier, problem
+}
@@ -0,0 +1,88 @@
+package svm
+
+// A MinMaxScaler shifts and scales the components of Samples so that they range from 0 to 1 over
+// the Problem it was fit to.
+//
+// Like a Standardizer, it stores its parameters, so the same transformation can be applied to new
+// samples before they are classified.
+// New samples may fall outside of [0, 1] if they are outside the range of the training samples.
+type MinMaxScaler struct {
+	Mins   []float64
+	Ranges []float64
+}
+
+// FitMinMaxScaler computes the minimum and range of every component of the samples in a Problem.
+//
+// Constant components are given a range of 1, so that they are mapped to 0 rather than divided
+// by zero.
+func FitMinMaxScaler(p *Problem) *MinMaxScaler {
+	count := p.sampleCount()
+	if count == 0 {
+		return &MinMaxScaler{}
+	}
+	first, _ := p.sample(0)
+	res := &MinMaxScaler{
+		Mins:   append([]float64{}, first.V...),
+		Ranges: make([]float64, len(first.V)),
+	}
+	maxes := append([]float64{}, first.V...)
+	for i := 1; i < count; i++ {
+		sample, _ := p.sample(i)
+		for j, x := range sample.V {
+			if x < res.Mins[j] {
+				res.Mins[j] = x
+			}
+			if x > maxes[j] {
+				maxes[j] = x
+			}
+		}
+	}
+	for j, high := range maxes {
+		if high == res.Mins[j] {
+			res.Ranges[j] = 1
+		} else {
+			res.Ranges[j] = high - res.Mins[j]
+		}
+	}
+	return res
+}
+
+// Transform returns a copy of a Problem with all of its samples scaled.
+// The Kernel and sample weights are preserved.
+func (m *MinMaxScaler) Transform(p *Problem) *Problem {
+	res := &Problem{
+		Positives:       make([]Sample, len(p.Positives)),
+		Negatives:       make([]Sample, len(p.Negatives)),
+		Kernel:          p.Kernel,
+		PositiveWeights: p.PositiveWeights,
+		NegativeWeights: p.NegativeWeights,
+	}
+	for i, sample := range p.Positives {
+		res.Positives[i] = m.TransformSample(sample)
+	}
+	for i, sample := range p.Negatives {
+		res.Negatives[i] = m.TransformSample(sample)
+	}
+	return res
+}
+
+// TransformSample returns a scaled copy of a Sample.
+// The UserInfo of the sample is preserved.
+func (m *MinMaxScaler) TransformSample(sample Sample) Sample {
+	res := Sample{V: make([]float64, len(sample.V)), UserInfo: sample.UserInfo}
+	for i, x := range sample.V {
+		res.V[i] = (x - m.Mins[i]) / m.Ranges[i]
+	}
+	return res
+}
+
+// InverseTransformSample undoes TransformSample, returning a copy of a scaled Sample in the
+// original units.
+// The UserInfo of the sample is preserved.
+func (m *MinMaxScaler) InverseTransformSample(sample Sample) Sample {
+	res := Sample{V: make([]float64, len(sample.V)), UserInfo: sample.UserInfo}
+	for i, x := range sample.V {
+		res.V[i] = x*m.Ranges[i] + m.Mins[i]
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package svm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMinMaxScaler(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 100, 3, 0)
+	for _, samples := range [][]Sample{problem.Positives, problem.Negatives} {
+		for _, sample := range samples {
+			sample.V[0] = sample.V[0]*10 + 5
+			sample.V[1] = sample.V[1]*0.1 - 3
+		}
+	}
+
+	scaler := FitMinMaxScaler(problem)
+	transformed := scaler.Transform(problem)
+	if transformed.Kernel == nil {
+		t.Error("kernel was not preserved")
+	}
+	for dim := 0; dim < 3; dim++ {
+		low, high := 1.0, 0.0
+		for i := 0; i < transformed.sampleCount(); i++ {
+			sample, _ := transformed.sample(i)
+			x := sample.V[dim]
+			if x < 0 || x > 1 {
+				t.Fatalf("dimension %d: value %f is outside [0, 1]", dim, x)
+			}
+			if x < low {
+				low = x
+			}
+			if x > high {
+				high = x
+			}
+		}
+		if low != 0 || high != 1 {
+			t.Errorf("dimension %d: range should be [0, 1] but got [%f, %f]", dim, low, high)
+		}
+	}
+
+	for i := 0; i < problem.sampleCount(); i++ {
+		original, _ := problem.sample(i)
+		scaled, _ := transformed.sample(i)
+		if !scaler.InverseTransformSample(scaled).Equal(original, 1e-12) {
+			t.Errorf("sample %d: inverse transform did not recover the original", i)
+		}
+	}
+}
+
+func TestMinMaxScalerConstant(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{1, 3}}, {V: []float64{3, 3}}},
+		Negatives: []Sample{{V: []float64{2, 3}}},
+		Kernel:    LinearKernel,
+	}
+	scaler := FitMinMaxScaler(problem)
+	actual := scaler.TransformSample(Sample{V: []float64{2, 3}, UserInfo: 7})
+	if actual.V[0] != 0.5 || actual.V[1] != 0 {
+		t.Error("unexpected sample:", actual.V)
+	}
+	if actual.UserInfo != 7 {
+		t.Error("UserInfo was not preserved")
+	}
+	if inverse := scaler.InverseTransformSample(actual); !inverse.Equal(NewSample(2, 3), 0) {
+		t.Error("unexpected inverse:", inverse.V)
+	}
+}
@@ -0,0 +1,141 @@
+package svm
+
+import "sort"
+
+// A MulticlassProblem is like a Problem, except that each sample belongs to one of an arbitrary
+// number of classes.
+type MulticlassProblem struct {
+	Samples []Sample
+
+	// Classes contains the class of each sample in Samples.
+	Classes []int
+
+	Kernel Kernel
+}
+
+// classList returns the distinct classes in the problem in ascending order.
+func (m *MulticlassProblem) classList() []int {
+	seen := map[int]bool{}
+	var res []int
+	for _, class := range m.Classes {
+		if !seen[class] {
+			seen[class] = true
+			res = append(res, class)
+		}
+	}
+	sort.Ints(res)
+	return res
+}
+
+// binaryProblem creates a Problem whose positives are the samples from the positive class and
+// whose negatives are the samples from any of the negative classes.
+func (m *MulticlassProblem) binaryProblem(positive int, negatives ...int) *Problem {
+	res := &Problem{Kernel: m.Kernel}
+	for i, class := range m.Classes {
+		if class == positive {
+			res.Positives = append(res.Positives, m.Samples[i])
+			continue
+		}
+		for _, negative := range negatives {
+			if class == negative {
+				res.Negatives = append(res.Negatives, m.Samples[i])
+				break
+			}
+		}
+	}
+	return res
+}
+
+// An OVRClassifier classifies samples into one of many classes using a "one-vs-rest" scheme.
+// There is one binary classifier per class, and a sample is assigned to the class whose
+// classifier gives it the highest rating.
+type OVRClassifier struct {
+	// Classes contains the classes in ascending order.
+	Classes []int
+
+	// Classifiers contains the binary classifier for each class in Classes.
+	Classifiers []*LinearClassifier
+}
+
+// TrainOVR trains an OVRClassifier, using a Solver to train each of the binary classifiers.
+func TrainOVR(p *MulticlassProblem, s Solver) *OVRClassifier {
+	res := &OVRClassifier{Classes: p.classList()}
+	for i, class := range res.Classes {
+		negatives := make([]int, 0, len(res.Classes)-1)
+		negatives = append(negatives, res.Classes[:i]...)
+		negatives = append(negatives, res.Classes[i+1:]...)
+		binary := p.binaryProblem(class, negatives...)
+		res.Classifiers = append(res.Classifiers, s.Solve(binary))
+	}
+	return res
+}
+
+// Classify returns the class of a sample.
+// If several classifiers give the sample the same rating, the lowest class wins.
+func (o *OVRClassifier) Classify(sample Sample) int {
+	var bestClass int
+	var bestRating float64
+	for i, classifier := range o.Classifiers {
+		rating := classifier.Rating(sample)
+		if i == 0 || rating > bestRating {
+			bestRating = rating
+			bestClass = o.Classes[i]
+		}
+	}
+	return bestClass
+}
+
+// A MulticlassClassifier classifies samples into one of many classes.
+// Both OVRClassifier and OVOClassifier implement this interface.
+type MulticlassClassifier interface {
+	Classify(sample Sample) int
+}
+
+// An OVOClassifier classifies samples into one of many classes using a "one-vs-one" scheme.
+// There is one binary classifier for every pair of classes, and a sample is assigned to the class
+// which wins the most pairwise votes.
+type OVOClassifier struct {
+	// Classes contains the classes in ascending order.
+	Classes []int
+
+	// Pairs contains the pair of classes for each classifier in Classifiers.
+	// A classifier's positives come from the first class of its pair.
+	Pairs [][2]int
+
+	Classifiers []*LinearClassifier
+}
+
+// TrainOVO trains an OVOClassifier, using a Solver to train each of the binary classifiers.
+func TrainOVO(p *MulticlassProblem, s Solver) *OVOClassifier {
+	res := &OVOClassifier{Classes: p.classList()}
+	for i, class1 := range res.Classes {
+		for _, class2 := range res.Classes[i+1:] {
+			binary := p.binaryProblem(class1, class2)
+			res.Pairs = append(res.Pairs, [2]int{class1, class2})
+			res.Classifiers = append(res.Classifiers, s.Solve(binary))
+		}
+	}
+	return res
+}
+
+// Classify returns the class of a sample.
+// If several classes receive the same number of votes, the lowest class wins.
+func (o *OVOClassifier) Classify(sample Sample) int {
+	votes := map[int]int{}
+	for i, classifier := range o.Classifiers {
+		if classifier.Classify(sample) {
+			votes[o.Pairs[i][0]]++
+		} else {
+			votes[o.Pairs[i][1]]++
+		}
+	}
+
+	var bestClass, bestVotes int
+	for i, class := range o.Classes {
+		if i == 0 || votes[class] > bestVotes {
+			bestClass = class
+			bestVotes = votes[class]
+		}
+	}
+	return bestClass
+}
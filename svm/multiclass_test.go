@@ -0,0 +1,85 @@
+package svm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestOVRClassifier(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	centers := [][]float64{{0, 4}, {-3.5, -2}, {3.5, -2}}
+	problem := clusterProblem(rng, centers, 20)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 1000, StepSize: 0.001}
+	classifier := TrainOVR(problem, solver)
+
+	if len(classifier.Classifiers) != 3 {
+		t.Fatal("unexpected number of classifiers:", len(classifier.Classifiers))
+	}
+	for i, sample := range problem.Samples {
+		if class := classifier.Classify(sample); class != problem.Classes[i] {
+			t.Errorf("sample %d: expected class %d but got %d", i, problem.Classes[i], class)
+		}
+	}
+}
+
+func TestOVOClassifier(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	centers := [][]float64{{0, 4}, {4, 0}, {0, -4}, {-4, 0}}
+	problem := clusterProblem(rng, centers, 20)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 1000, StepSize: 0.001}
+	classifier := TrainOVO(problem, solver)
+
+	if len(classifier.Classifiers) != 6 {
+		t.Fatal("unexpected number of classifiers:", len(classifier.Classifiers))
+	}
+	for i, sample := range problem.Samples {
+		if class := classifier.Classify(sample); class != problem.Classes[i] {
+			t.Errorf("sample %d: expected class %d but got %d", i, problem.Classes[i], class)
+		}
+	}
+}
+
+func TestOVOClassifierTies(t *testing.T) {
+	// Each classifier votes for a fixed class, giving classes 1, 2, and 3 two votes each.
+	winners := map[[2]int]int{
+		{0, 1}: 1, {0, 2}: 2, {0, 3}: 3,
+		{1, 2}: 2, {1, 3}: 1, {2, 3}: 3,
+	}
+	classifier := &OVOClassifier{Classes: []int{0, 1, 2, 3}}
+	for i := 0; i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			pair := [2]int{i, j}
+			threshold := -1.0
+			if winners[pair] == i {
+				threshold = 1
+			}
+			classifier.Pairs = append(classifier.Pairs, pair)
+			classifier.Classifiers = append(classifier.Classifiers, &LinearClassifier{
+				HyperplaneNormal: Sample{V: []float64{0}},
+				Threshold:        threshold,
+				Kernel:           LinearKernel,
+			})
+		}
+	}
+
+	if class := classifier.Classify(Sample{V: []float64{1}}); class != 1 {
+		t.Error("expected tie to go to class 1 but got", class)
+	}
+}
+
+// clusterProblem generates a MulticlassProblem with a cluster of samples around each center.
+// The i-th cluster's samples belong to class i.
+func clusterProblem(rng *rand.Rand, centers [][]float64, count int) *MulticlassProblem {
+	res := &MulticlassProblem{Kernel: LinearKernel}
+	for class, center := range centers {
+		for i := 0; i < count; i++ {
+			vec := make([]float64, len(center))
+			for j, x := range center {
+				vec[j] = x + rng.NormFloat64()*0.5
+			}
+			res.Samples = append(res.Samples, Sample{V: vec})
+			res.Classes = append(res.Classes, class)
+		}
+	}
+	return res
+}
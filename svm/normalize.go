@@ -0,0 +1,48 @@
+package svm
+
+import "math"
+
+// Normalize returns a copy of a Problem with every sample scaled to unit Euclidean length (see
+// NormalizeSample).
+// The Kernel and sample weights are preserved.
+//
+// Unlike a Standardizer or a MinMaxScaler, which scale each component separately, this scales
+// each sample separately, so it needs no fitted parameters.
+// It is commonly used for bag-of-words features, where it makes LinearKernel compute the cosine
+// similarity of two samples.
+func Normalize(p *Problem) *Problem {
+	res := &Problem{
+		Positives:       make([]Sample, len(p.Positives)),
+		Negatives:       make([]Sample, len(p.Negatives)),
+		Kernel:          p.Kernel,
+		PositiveWeights: p.PositiveWeights,
+		NegativeWeights: p.NegativeWeights,
+	}
+	for i, sample := range p.Positives {
+		res.Positives[i] = NormalizeSample(sample)
+	}
+	for i, sample := range p.Negatives {
+		res.Negatives[i] = NormalizeSample(sample)
+	}
+	return res
+}
+
+// NormalizeSample returns a copy of a Sample scaled to unit Euclidean length.
+// A Sample whose components are all zero is copied unchanged.
+// The UserInfo of the sample is preserved.
+func NormalizeSample(sample Sample) Sample {
+	res := sample.Clone()
+	norm := math.Sqrt(LinearKernel(sample, sample))
+	if norm == 0 {
+		return res
+	}
+	for i := range res.V {
+		res.V[i] /= norm
+	}
+	if res.Sparse != nil {
+		for i := range res.Sparse.Values {
+			res.Sparse.Values[i] /= norm
+		}
+	}
+	return res
+}
@@ -0,0 +1,44 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 4, 0)
+	normalized := Normalize(problem)
+	if normalized.Kernel == nil {
+		t.Error("kernel was not preserved")
+	}
+	for i := 0; i < normalized.sampleCount(); i++ {
+		sample, _ := normalized.sample(i)
+		if norm := math.Sqrt(LinearKernel(sample, sample)); math.Abs(norm-1) > 1e-12 {
+			t.Errorf("sample %d: norm should be 1 but got %f", i, norm)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		s1, s2 := gaussianSample(rng, 4), gaussianSample(rng, 4)
+		s1.V[0] *= 100
+		cosine := LinearKernel(s1, s2) /
+			math.Sqrt(LinearKernel(s1, s1)*LinearKernel(s2, s2))
+		actual := LinearKernel(NormalizeSample(s1), NormalizeSample(s2))
+		if math.Abs(actual-cosine) > 1e-12 {
+			t.Errorf("product should be %f but got %f", cosine, actual)
+		}
+	}
+
+	zero := Sample{V: []float64{0, 0, 0}, UserInfo: 3}
+	if actual := NormalizeSample(zero); !actual.Equal(zero, 0) || actual.UserInfo != 3 {
+		t.Error("unexpected normalized zero sample:", actual)
+	}
+	original := NewSample(3, 4)
+	if actual := NormalizeSample(original); !actual.Equal(NewSample(0.6, 0.8), 1e-12) {
+		t.Error("unexpected normalized sample:", actual.V)
+	} else if original.V[0] != 3 {
+		t.Error("original sample was modified")
+	}
+}
@@ -0,0 +1,95 @@
+package svm
+
+import (
+	"fmt"
+	"math"
+)
+
+// A NuSubgradientSolver solves Problems with linear kernels using sub-gradient descent on the
+// nu-SVM formulation of the soft-margin problem.
+//
+// Rather than fixing the margin at 1 and trading it off against the magnitude of the normal, the
+// nu-SVM learns the width of the margin, rho, and minimizes
+//
+//	|normal|^2/2 - Nu*rho + mean(max(0, rho - y*(normal*x + threshold)))
+//
+// The resulting Nu is much easier to choose than SubgradientSolver's Tradeoff, whose meaningful
+// range depends on the data.
+// The nu-SVM is equivalent to the usual soft-margin problem, with a Tradeoff determined by Nu and
+// the data.
+type NuSubgradientSolver struct {
+	// Nu, which must be in (0, 1], is an upper bound on the fraction of samples which violate the
+	// margin and a lower bound on the fraction of samples which are support vectors.
+	Nu float64
+
+	// Steps indicates how many descents the solver should make before returning its solution.
+	Steps int
+
+	// StepSize determines how much of the gradient should be added to the current solution at
+	// each step.
+	StepSize float64
+
+	// StepSchedule, if non-nil, is used to compute the step size for each step, overriding
+	// StepSize.
+	StepSchedule StepSchedule
+}
+
+// Solve solves a Problem, which must use LinearKernel.
+//
+// The solution is scaled so that the learned margin lies at decision values of -1 and 1, as it
+// does for the other solvers, so that LinearClassifier.SupportVectors and MarginWidth apply.
+// It panics if the Problem is invalid or if s.Nu is not in (0, 1].
+func (s *NuSubgradientSolver) Solve(p *Problem) *LinearClassifier {
+	if err := p.Validate(); err != nil {
+		panic("invalid problem: " + err.Error())
+	} else if !isLinearKernel(p.Kernel) {
+		panic("NuSubgradientSolver requires a linear kernel")
+	} else if !(s.Nu > 0 && s.Nu <= 1) {
+		panic(fmt.Sprintf("nu must be in (0, 1] but got %g", s.Nu))
+	}
+
+	var totalWeight float64
+	for i := 0; i < p.sampleCount(); i++ {
+		totalWeight += p.sampleWeight(i)
+	}
+
+	normal := make([]float64, p.Positives[0].Dim())
+	var threshold, rho float64
+	for step := 0; step < s.Steps; step++ {
+		normalGrad := make([]float64, len(normal))
+		copy(normalGrad, normal)
+		var thresholdGrad float64
+		rhoGrad := -s.Nu
+
+		normalSample := Sample{V: normal}
+		for i := 0; i < p.sampleCount(); i++ {
+			sample, sign := p.sample(i)
+			if sign*(LinearKernel(normalSample, sample)+threshold) >= rho {
+				continue
+			}
+			scale := p.sampleWeight(i) / totalWeight
+			addScaled(normalGrad, sample, -scale*sign)
+			thresholdGrad -= scale * sign
+			rhoGrad += scale
+		}
+
+		stepSize := scheduledStepSize(s.StepSchedule, s.StepSize, step)
+		for i, x := range normalGrad {
+			normal[i] -= stepSize * x
+		}
+		threshold -= stepSize * thresholdGrad
+		rho = math.Max(0, rho-stepSize*rhoGrad)
+	}
+
+	if rho > 0 {
+		for i := range normal {
+			normal[i] /= rho
+		}
+		threshold /= rho
+	}
+	return &LinearClassifier{
+		HyperplaneNormal: Sample{V: normal},
+		Threshold:        threshold,
+		Kernel:           p.Kernel,
+	}
+}
@@ -0,0 +1,52 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNuSubgradientSolver(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 200, 2, 0.05)
+
+	lastViolations := -1.0
+	for _, nu := range []float64{0.1, 0.3, 0.6} {
+		solver := &NuSubgradientSolver{
+			Nu:           nu,
+			Steps:        5000,
+			StepSchedule: InverseTimeDecay(0.01, 0.001),
+		}
+		classifier := solver.Solve(problem)
+
+		var violations int
+		for i := 0; i < problem.sampleCount(); i++ {
+			sample, sign := problem.sample(i)
+			if sign*classifier.DecisionValue(sample) < 1 {
+				violations++
+			}
+		}
+		fraction := float64(violations) / float64(problem.sampleCount())
+		if fraction <= lastViolations {
+			t.Errorf("violations did not increase for nu=%f: %f", nu, fraction)
+		}
+		if fraction < nu-0.1 || fraction > nu+0.1 {
+			t.Errorf("violation fraction %f is far from nu=%f", fraction, nu)
+		}
+		lastViolations = fraction
+	}
+}
+
+func TestNuSubgradientSolverInvalidNu(t *testing.T) {
+	problem := randomLinearProblem(rand.New(rand.NewSource(1)), 5, 2, 0)
+	for _, nu := range []float64{0, -0.1, 1.1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for nu=%f", nu)
+				}
+			}()
+			(&NuSubgradientSolver{Nu: nu, Steps: 1, StepSize: 0.1}).Solve(problem)
+		}()
+	}
+}
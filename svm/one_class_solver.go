@@ -0,0 +1,83 @@
+package svm
+
+import "fmt"
+
+// A OneClassSolver finds the region of sample space occupied by a set of samples, for detecting
+// novel samples (outliers) which do not belong to it.
+//
+// This uses Schölkopf's one-class formulation, which finds a hyperplane separating the samples
+// from the origin in the feature space of the Kernel with as wide a margin as possible.
+// It is optimized using sub-gradient descent in feature space, as in KernelSubgradientSolver.
+// RadialBasisKernel is the usual choice of kernel.
+type OneClassSolver struct {
+	// Nu, which must be in (0, 1], is an upper bound on the fraction of training samples which lie
+	// outside of the region, and a lower bound on the fraction of samples which are support
+	// vectors.
+	Nu float64
+
+	// Steps indicates how many descents the solver should make before returning its solution.
+	Steps int
+
+	// StepSize determines how much of the gradient should be added to the current solution at
+	// each step.
+	StepSize float64
+
+	// StepSchedule, if non-nil, is used to compute the step size for each step, overriding
+	// StepSize.
+	StepSchedule StepSchedule
+}
+
+// Solve finds the region occupied by the positive samples of a Problem.
+// The negative samples are ignored.
+//
+// The resulting classifier classifies samples inside of the region as positive and outliers as
+// negative.
+// Solve panics if the Problem has no positive samples, if s.Nu is not in (0, 1], or if the
+// Problem has per-sample weights, which OneClassSolver does not support.
+func (s *OneClassSolver) Solve(p *Problem) *CombinationClassifier {
+	if len(p.Positives) == 0 {
+		panic("invalid problem: " + ErrNoPositives.Error())
+	} else if !(s.Nu > 0 && s.Nu <= 1) {
+		panic(fmt.Sprintf("nu must be in (0, 1] but got %g", s.Nu))
+	} else if p.weighted() {
+		panic("OneClassSolver does not support sample weights")
+	}
+	samples := p.Positives
+	cache := p.indexedKernel(samples)
+	violationScale := 1 / (s.Nu * float64(len(samples)))
+
+	coeffs := make([]float64, len(samples))
+	products := make([]float64, len(samples))
+	var offset float64
+	for step := 0; step < s.Steps; step++ {
+		for i := range products {
+			var product float64
+			for j, coeff := range coeffs {
+				if coeff != 0 {
+					product += coeff * cache.At(i, j)
+				}
+			}
+			products[i] = product
+		}
+
+		stepSize := scheduledStepSize(s.StepSchedule, s.StepSize, step)
+		var violations int
+		for i, product := range products {
+			coeffs[i] *= 1 - stepSize
+			if product < offset {
+				coeffs[i] += stepSize * violationScale
+				violations++
+			}
+		}
+		offset -= stepSize * (float64(violations)*violationScale - 1)
+	}
+
+	res := &CombinationClassifier{Threshold: -offset, Kernel: p.Kernel}
+	for i, coeff := range coeffs {
+		if coeff != 0 {
+			res.SupportVectors = append(res.SupportVectors, samples[i])
+			res.Coefficients = append(res.Coefficients, coeff)
+		}
+	}
+	return res
+}
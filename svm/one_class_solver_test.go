@@ -0,0 +1,67 @@
+package svm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestOneClassSolver(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &Problem{Kernel: RadialBasisKernel(0.5)}
+	for i := 0; i < 200; i++ {
+		problem.Positives = append(problem.Positives, gaussianSample(rng, 2))
+	}
+	solver := &OneClassSolver{
+		Nu:           0.1,
+		Steps:        500,
+		StepSchedule: InverseTimeDecay(0.1, 0.01),
+	}
+	solution := solver.Solve(problem)
+
+	for _, x := range [][]float64{{0, 0}, {0.5, -0.3}, {-0.4, 0.2}} {
+		if !solution.Classify(Sample{V: x}) {
+			t.Error("interior point flagged as outlier:", x)
+		}
+	}
+	for _, x := range [][]float64{{5, 5}, {-4, 0}, {0, 6}} {
+		if solution.Classify(Sample{V: x}) {
+			t.Error("distant point not flagged as outlier:", x)
+		}
+	}
+
+	var outliers int
+	for _, x := range problem.Positives {
+		if !solution.Classify(x) {
+			outliers++
+		}
+	}
+	if fraction := float64(outliers) / float64(len(problem.Positives)); fraction > 0.2 {
+		t.Error("too many training outliers:", fraction)
+	}
+}
+
+func TestOneClassSolverInvalid(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{NewSample(1, 2), NewSample(2, 1)},
+		Kernel:    RadialBasisKernel(0.5),
+	}
+	cases := map[string]func(){
+		"nu=0":        func() { (&OneClassSolver{Steps: 1}).Solve(problem) },
+		"nu<0":        func() { (&OneClassSolver{Nu: -0.5, Steps: 1}).Solve(problem) },
+		"nu>1":        func() { (&OneClassSolver{Nu: 1.5, Steps: 1}).Solve(problem) },
+		"no positive": func() { (&OneClassSolver{Nu: 0.5, Steps: 1}).Solve(&Problem{}) },
+	}
+	for name, solve := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			solve()
+		}()
+	}
+	if res := (&OneClassSolver{Nu: 1, Steps: 1}).Solve(problem); res == nil {
+		t.Error("nu=1 should be accepted")
+	}
+}
@@ -0,0 +1,122 @@
+package svm
+
+import "math"
+
+const defaultOptimizerEpsilon = 1e-8
+
+// An Optimizer determines the direction of each step taken by a SubgradientSolver.
+//
+// The parameters being optimized are the components of the normal followed by the threshold.
+type Optimizer interface {
+	// NewState creates the state for a single optimization of dim parameters.
+	// A SubgradientSolver creates a new state every time it solves a Problem, so an Optimizer
+	// may be shared between solvers.
+	NewState(dim int) OptimizerState
+}
+
+// An OptimizerState stores whatever an Optimizer accumulates from step to step.
+type OptimizerState interface {
+	// Direction computes the direction of the next step from the gradient at the current
+	// parameters.
+	// The solver subtracts the direction, scaled by the step size, from the parameters.
+	Direction(grad []float64) []float64
+}
+
+// Adagrad is an Optimizer which adapts the step size of each parameter to the history of its
+// partial derivatives.
+//
+// Each partial derivative is divided by the square root of the sum of its squares over all of
+// the steps so far.
+// This makes large steps for parameters whose partials are rarely non-zero, such as the weights
+// of rare features, and small steps for parameters which are updated frequently.
+type Adagrad struct {
+	// Epsilon is added to the denominator of each update to avoid division by zero.
+	// If this is zero, a default of 1e-8 is used.
+	Epsilon float64
+}
+
+func (a *Adagrad) NewState(dim int) OptimizerState {
+	epsilon := a.Epsilon
+	if epsilon == 0 {
+		epsilon = defaultOptimizerEpsilon
+	}
+	return &adagradState{epsilon: epsilon, squareSums: make([]float64, dim)}
+}
+
+type adagradState struct {
+	epsilon    float64
+	squareSums []float64
+}
+
+func (a *adagradState) Direction(grad []float64) []float64 {
+	res := make([]float64, len(grad))
+	for i, x := range grad {
+		a.squareSums[i] += x * x
+		res[i] = x / (math.Sqrt(a.squareSums[i]) + a.epsilon)
+	}
+	return res
+}
+
+// Adam is an Optimizer which steps along a running average of the gradients, with the step size
+// of each parameter scaled down by a running average of its squared partials.
+//
+// Since the size of each step depends little on the size of the gradient, Adam is much less
+// sensitive to the choice of step size than plain descent.
+// See Kingma and Ba's "Adam: A Method for Stochastic Optimization".
+type Adam struct {
+	// Beta1 is the decay rate of the running average of the gradients.
+	// If this is zero, a default of 0.9 is used.
+	Beta1 float64
+
+	// Beta2 is the decay rate of the running average of the squared partials.
+	// If this is zero, a default of 0.999 is used.
+	Beta2 float64
+
+	// Epsilon is added to the denominator of each update to avoid division by zero.
+	// If this is zero, a default of 1e-8 is used.
+	Epsilon float64
+}
+
+func (a *Adam) NewState(dim int) OptimizerState {
+	res := &adamState{
+		beta1:   a.Beta1,
+		beta2:   a.Beta2,
+		epsilon: a.Epsilon,
+		moment1: make([]float64, dim),
+		moment2: make([]float64, dim),
+	}
+	if res.beta1 == 0 {
+		res.beta1 = 0.9
+	}
+	if res.beta2 == 0 {
+		res.beta2 = 0.999
+	}
+	if res.epsilon == 0 {
+		res.epsilon = defaultOptimizerEpsilon
+	}
+	return res
+}
+
+type adamState struct {
+	beta1   float64
+	beta2   float64
+	epsilon float64
+
+	moment1 []float64
+	moment2 []float64
+	steps   int
+}
+
+func (a *adamState) Direction(grad []float64) []float64 {
+	a.steps++
+	correction1 := 1 - math.Pow(a.beta1, float64(a.steps))
+	correction2 := 1 - math.Pow(a.beta2, float64(a.steps))
+
+	res := make([]float64, len(grad))
+	for i, x := range grad {
+		a.moment1[i] = a.beta1*a.moment1[i] + (1-a.beta1)*x
+		a.moment2[i] = a.beta2*a.moment2[i] + (1-a.beta2)*x*x
+		res[i] = (a.moment1[i] / correction1) / (math.Sqrt(a.moment2[i]/correction2) + a.epsilon)
+	}
+	return res
+}
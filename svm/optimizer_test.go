@@ -0,0 +1,75 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAdagrad(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := rareFeatureProblem(rng, 200, 50)
+
+	// Give each method the benefit of its best step size.
+	plainObjective := math.Inf(1)
+	adagradObjective := math.Inf(1)
+	for _, stepSize := range []float64{0.01, 0.1, 1} {
+		plain := &SubgradientSolver{Tradeoff: 0.001, Steps: 50, StepSize: stepSize}
+		adagrad := *plain
+		adagrad.Optimizer = &Adagrad{}
+		plainObjective = math.Min(plainObjective, plain.objective(problem, plain.Solve(problem)))
+		adagradObjective = math.Min(adagradObjective,
+			adagrad.objective(problem, adagrad.Solve(problem)))
+	}
+	if adagradObjective >= plainObjective/2 {
+		t.Errorf("Adagrad objective %f should be well below plain objective %f",
+			adagradObjective, plainObjective)
+	}
+}
+
+// rareFeatureProblem generates a Problem where every sample has an uninformative first feature
+// which is randomly -1 or 1, and a single informative feature, chosen at random from the rest,
+// whose sign gives the class of the sample.
+// The partials for the first feature are large, while the partials for each informative feature
+// are small, since each of them only appears in a small fraction of the samples.
+func rareFeatureProblem(rng *rand.Rand, count, dim int) *Problem {
+	res := &Problem{Kernel: LinearKernel}
+	for i := 0; i < count; i++ {
+		sample := Sample{V: make([]float64, dim)}
+		sample.V[0] = float64(rng.Intn(2)*2 - 1)
+		feature := 1 + rng.Intn(dim-1)
+		if i%2 == 0 {
+			sample.V[feature] = 1
+			res.Positives = append(res.Positives, sample)
+		} else {
+			sample.V[feature] = -1
+			res.Negatives = append(res.Negatives, sample)
+		}
+	}
+	return res
+}
+
+func TestAdam(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 100, 3, 0.05)
+	tuned := &SubgradientSolver{Tradeoff: 0.01, Steps: 200, StepSize: 0.1}
+	tunedObjective := tuned.objective(problem, tuned.Solve(problem))
+
+	var worstPlain, worstAdam float64
+	for _, stepSize := range []float64{0.3, 1, 3, 10} {
+		plain := *tuned
+		plain.StepSize = stepSize
+		adam := plain
+		adam.Optimizer = &Adam{}
+		worstPlain = math.Max(worstPlain, plain.objective(problem, plain.Solve(problem)))
+		worstAdam = math.Max(worstAdam, adam.objective(problem, adam.Solve(problem)))
+	}
+	if worstAdam > 2.5*tunedObjective {
+		t.Errorf("Adam objective %f should be close to tuned objective %f", worstAdam,
+			tunedObjective)
+	}
+	if worstPlain < 10*worstAdam {
+		t.Errorf("plain objective %f should be far above Adam objective %f", worstPlain,
+			worstAdam)
+	}
+}
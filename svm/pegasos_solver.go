@@ -0,0 +1,145 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// A PegasosSolver solves Problems using the Pegasos stochastic sub-gradient algorithm.
+//
+// Rather than looking at every sample during every step, Pegasos picks a random mini-batch of
+// samples and descends along the sub-gradient of the regularized hinge loss for that batch,
+// using a step size of 1/(Lambda*t) for the t-th step.
+//
+// Like SubgradientSolver, this is only effective for linear kernels.
+type PegasosSolver struct {
+	// Lambda determines how important a wide margin is, much like SubgradientSolver's Tradeoff.
+	// It must be greater than zero.
+	Lambda float64
+
+	// Iterations is the number of mini-batch steps to take.
+	// It is ignored if Epochs is set.
+	Iterations int
+
+	// Epochs, if non-zero, makes the solver pass over the samples in order rather than picking
+	// each mini-batch at random.
+	// Each epoch splits the samples into consecutive mini-batches, the last of which may be
+	// smaller than BatchSize, and takes a step for each of them.
+	Epochs int
+
+	// Shuffle, if true, shuffles the samples using Rand at the start of every epoch.
+	// Without it, every epoch visits the positives followed by the negatives in the same order,
+	// which biases the updates within each epoch.
+	// It has no effect unless Epochs is set.
+	Shuffle bool
+
+	// BatchSize is the number of samples used for each step.
+	// If this is zero or negative, a single sample is used per step.
+	BatchSize int
+
+	// Project indicates whether the normal should be projected onto the ball of radius
+	// 1/sqrt(Lambda) after each step, as suggested in the original paper.
+	Project bool
+
+	// Rand is the source of randomness used to pick mini-batches or shuffle the samples.
+	// Using a seeded source makes the solver deterministic.
+	// If this is nil, a new source seeded from the current time is used for every solve.
+	Rand *rand.Rand
+}
+
+// Solve runs Pegasos on a Problem.
+// It panics if the Problem has per-sample weights, which PegasosSolver does not support.
+func (s *PegasosSolver) Solve(p *Problem) *LinearClassifier {
+	if p.weighted() {
+		panic("PegasosSolver does not support sample weights")
+	}
+	args := softMarginArgs{
+		normal: make([]float64, p.Positives[0].Dim()),
+	}
+
+	rng := s.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	if s.Epochs != 0 {
+		s.solveEpochs(p, &args, rng)
+		return args.classifier(p)
+	}
+
+	batch := make([]int, s.batchSize())
+	for t := 1; t <= s.Iterations; t++ {
+		for i := range batch {
+			batch[i] = rng.Intn(p.sampleCount())
+		}
+		s.step(p, &args, batch, t)
+	}
+
+	return args.classifier(p)
+}
+
+// solveEpochs takes s.Epochs passes over the samples of a Problem.
+func (s *PegasosSolver) solveEpochs(p *Problem, args *softMarginArgs, rng *rand.Rand) {
+	order := make([]int, p.sampleCount())
+	for i := range order {
+		order[i] = i
+	}
+
+	t := 1
+	for epoch := 0; epoch < s.Epochs; epoch++ {
+		if s.Shuffle {
+			rng.Shuffle(len(order), func(i, j int) {
+				order[i], order[j] = order[j], order[i]
+			})
+		}
+		for start := 0; start < len(order); start += s.batchSize() {
+			end := start + s.batchSize()
+			if end > len(order) {
+				end = len(order)
+			}
+			s.step(p, args, order[start:end], t)
+			t++
+		}
+	}
+}
+
+func (s *PegasosSolver) step(p *Problem, args *softMarginArgs, batch []int, t int) {
+	normalSample := Sample{V: args.normal}
+	stepSize := 1 / (s.Lambda * float64(t))
+	batchScale := stepSize / float64(len(batch))
+
+	normalChange := make([]float64, len(args.normal))
+	var thresholdChange float64
+	for _, idx := range batch {
+		sample, sign := p.sample(idx)
+		if sign*(p.Kernel(normalSample, sample)+args.threshold) < 1 {
+			addScaled(normalChange, sample, sign*batchScale)
+			thresholdChange += sign * batchScale
+		}
+	}
+
+	decay := 1 - stepSize*s.Lambda
+	for i, x := range normalChange {
+		args.normal[i] = args.normal[i]*decay + x
+	}
+	args.threshold += thresholdChange
+
+	if s.Project {
+		mag := math.Sqrt(p.Kernel(normalSample, normalSample))
+		maxMag := 1 / math.Sqrt(s.Lambda)
+		if mag > maxMag {
+			scale := maxMag / mag
+			for i := range args.normal {
+				args.normal[i] *= scale
+			}
+		}
+	}
+}
+
+func (s *PegasosSolver) batchSize() int {
+	if s.BatchSize <= 0 {
+		return 1
+	}
+	return s.BatchSize
+}
@@ -0,0 +1,181 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPegasosSolverAccuracy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	training := randomLinearProblem(rng, 400, 5, 0.1)
+	validation := &Problem{
+		Positives: training.Positives[200:],
+		Negatives: training.Negatives[200:],
+		Kernel:    training.Kernel,
+	}
+	training.Positives = training.Positives[:200]
+	training.Negatives = training.Negatives[:200]
+
+	baseline := &SubgradientSolver{
+		Tradeoff: 0.001,
+		Steps:    200,
+		StepSize: 0.001,
+	}
+	baselineAccuracy := problemAccuracy(baseline.Solve(training), validation)
+
+	// Two passes worth of samples, compared to the baseline's 200 full passes.
+	pegasos := &PegasosSolver{
+		Lambda:     0.001,
+		Iterations: 80,
+		BatchSize:  10,
+		Project:    true,
+		Rand:       rand.New(rand.NewSource(1)),
+	}
+	pegasosAccuracy := problemAccuracy(pegasos.Solve(training), validation)
+
+	if pegasosAccuracy < baselineAccuracy-0.05 {
+		t.Errorf("pegasos accuracy %f is much worse than baseline %f", pegasosAccuracy,
+			baselineAccuracy)
+	}
+}
+
+func TestPegasosSolverDeterminism(t *testing.T) {
+	problem := randomLinearProblem(rand.New(rand.NewSource(1)), 100, 5, 0.1)
+	solve := func() *LinearClassifier {
+		solver := &PegasosSolver{
+			Lambda:     0.01,
+			Iterations: 50,
+			BatchSize:  5,
+			Rand:       rand.New(rand.NewSource(1337)),
+		}
+		return solver.Solve(problem)
+	}
+
+	expected, actual := solve(), solve()
+	if actual.Threshold != expected.Threshold {
+		t.Errorf("threshold should be %v but got %v", expected.Threshold, actual.Threshold)
+	}
+	for i, x := range expected.HyperplaneNormal.V {
+		if actual.HyperplaneNormal.V[i] != x {
+			t.Errorf("component %d should be %v but got %v", i, x, actual.HyperplaneNormal.V[i])
+		}
+	}
+}
+
+func TestPegasosSolverNegativeBatchSize(t *testing.T) {
+	problem := randomLinearProblem(rand.New(rand.NewSource(1)), 100, 5, 0.1)
+	for _, epochs := range []int{0, 2} {
+		solve := func(batchSize int) *LinearClassifier {
+			solver := &PegasosSolver{
+				Lambda:     0.01,
+				Iterations: 50,
+				Epochs:     epochs,
+				BatchSize:  batchSize,
+				Rand:       rand.New(rand.NewSource(1337)),
+			}
+			return solver.Solve(problem)
+		}
+		expected, actual := solve(0), solve(-3)
+		if actual.Threshold != expected.Threshold ||
+			!actual.HyperplaneNormal.Equal(expected.HyperplaneNormal, 0) {
+			t.Errorf("epochs %d: negative batch size should behave like the default", epochs)
+		}
+	}
+}
+
+func TestPegasosSolverEpochs(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem, validation := randomLinearProblem(rng, 200, 5, 0.1).Split(0.5, rng)
+	solve := func(seed int64) *LinearClassifier {
+		solver := &PegasosSolver{
+			Lambda:    0.001,
+			Epochs:    3,
+			Shuffle:   true,
+			BatchSize: 16,
+			Rand:      rand.New(rand.NewSource(seed)),
+		}
+		return solver.Solve(problem)
+	}
+
+	sameSolution := func(c1, c2 *LinearClassifier) bool {
+		return c1.Threshold == c2.Threshold &&
+			reflect.DeepEqual(c1.HyperplaneNormal, c2.HyperplaneNormal)
+	}
+	expected := solve(1337)
+	if !sameSolution(solve(1337), expected) {
+		t.Error("solutions differ for the same seed")
+	}
+	if sameSolution(solve(1338), expected) {
+		t.Error("solutions are identical for different seeds")
+	}
+	if accuracy := problemAccuracy(expected, validation); accuracy < 0.9 {
+		t.Error("bad validation accuracy:", accuracy)
+	}
+}
+
+func TestPegasosSolverEpochsUnshuffled(t *testing.T) {
+	problem := randomLinearProblem(rand.New(rand.NewSource(1)), 10, 2, 0.1)
+	solver := &PegasosSolver{Lambda: 0.01, Epochs: 2, BatchSize: 3}
+
+	// Without shuffling, the batches are the same consecutive runs of samples every epoch, so
+	// the solution is deterministic even without a seeded Rand.
+	expected := softMarginArgs{normal: make([]float64, 2)}
+	step := 1
+	for epoch := 0; epoch < 2; epoch++ {
+		for _, batch := range [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}, {9, 10, 11}, {12, 13, 14},
+			{15, 16, 17}, {18, 19}} {
+			solver.step(problem, &expected, batch, step)
+			step++
+		}
+	}
+	actual := solver.Solve(problem)
+	if actual.Threshold != expected.threshold ||
+		!reflect.DeepEqual(actual.HyperplaneNormal.V, expected.normal) {
+		t.Error("unexpected solution")
+	}
+}
+
+// randomLinearProblem generates a linearly separable Problem with count samples per class.
+// No sample is closer than margin to the separating hyperplane.
+func randomLinearProblem(rng *rand.Rand, count, dim int, margin float64) *Problem {
+	normal := make([]float64, dim)
+	for i := range normal {
+		normal[i] = rng.NormFloat64()
+	}
+	mag := math.Sqrt(LinearKernel(Sample{V: normal}, Sample{V: normal}))
+	for i := range normal {
+		normal[i] /= mag
+	}
+
+	res := &Problem{Kernel: LinearKernel}
+	for len(res.Positives) < count || len(res.Negatives) < count {
+		vec := make([]float64, dim)
+		for i := range vec {
+			vec[i] = rng.Float64()*2 - 1
+		}
+		dot := LinearKernel(Sample{V: vec}, Sample{V: normal})
+		if dot > margin && len(res.Positives) < count {
+			res.Positives = append(res.Positives, Sample{V: vec})
+		} else if dot < -margin && len(res.Negatives) < count {
+			res.Negatives = append(res.Negatives, Sample{V: vec})
+		}
+	}
+	return res
+}
+
+func problemAccuracy(c Classifier, p *Problem) float64 {
+	var correct int
+	for _, x := range p.Positives {
+		if c.Classify(x) {
+			correct++
+		}
+	}
+	for _, x := range p.Negatives {
+		if !c.Classify(x) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(p.Positives)+len(p.Negatives))
+}
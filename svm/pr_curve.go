@@ -0,0 +1,73 @@
+package svm
+
+import (
+	"math"
+	"sort"
+)
+
+// A PRPoint is a point on a precision-recall curve.
+type PRPoint struct {
+	// Threshold is the smallest decision value which is classified as positive at this point.
+	Threshold float64
+
+	Recall    float64
+	Precision float64
+}
+
+// PRCurve computes the precision-recall curve of a Classifier on a Problem, along with the
+// average precision.
+//
+// Like ROC, the curve is generated by sweeping a threshold over the decision values (as returned
+// by c.Rating) from highest to lowest, and samples with equal decision values form a single step.
+// For imbalanced Problems, the precision-recall curve is usually more informative than the ROC
+// curve, since it does not reward correctly classifying the many negatives.
+//
+// The curve starts at a recall of 0 and a precision of 1, where no samples are positive (so
+// that no positive classification is wrong), and ends at a recall of 1, where every sample is.
+//
+// The average precision is the mean of the precision at each step, weighted by how much the step
+// increases the recall.
+// It is 1 for a perfect classifier, and near the fraction of positives for a random one.
+// If the Problem has no positives, the curve is nil and the average precision is NaN.
+func PRCurve(c Classifier, p *Problem) ([]PRPoint, float64) {
+	if len(p.Positives) == 0 {
+		return nil, math.NaN()
+	}
+
+	type scoredSample struct {
+		value    float64
+		positive bool
+	}
+	scores := make([]scoredSample, p.sampleCount())
+	for i := range scores {
+		sample, sign := p.sample(i)
+		scores[i] = scoredSample{c.Rating(sample), sign > 0}
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].value > scores[j].value
+	})
+
+	numPos := float64(len(p.Positives))
+	points := []PRPoint{{Threshold: math.Inf(1), Precision: 1}}
+	var averagePrecision float64
+	var truePos, predictedPos int
+	for i := 0; i < len(scores); {
+		threshold := scores[i].value
+		for ; i < len(scores) && scores[i].value == threshold; i++ {
+			predictedPos++
+			if scores[i].positive {
+				truePos++
+			}
+		}
+		last := points[len(points)-1]
+		point := PRPoint{
+			Threshold: threshold,
+			Recall:    float64(truePos) / numPos,
+			Precision: float64(truePos) / float64(predictedPos),
+		}
+		averagePrecision += (point.Recall - last.Recall) * point.Precision
+		points = append(points, point)
+	}
+
+	return points, averagePrecision
+}
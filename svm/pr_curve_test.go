@@ -0,0 +1,71 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPRCurveSeparable(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+	points, averagePrecision := PRCurve(solver.Solve(problem), problem)
+	if averagePrecision != 1 {
+		t.Error("expected average precision 1 but got", averagePrecision)
+	}
+	first, last := points[0], points[len(points)-1]
+	if first.Recall != 0 || first.Precision != 1 {
+		t.Error("bad first point:", first)
+	}
+	if last.Recall != 1 || last.Precision != 0.5 {
+		t.Error("bad last point:", last)
+	}
+}
+
+func TestPRCurveRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &Problem{Kernel: LinearKernel}
+	for i := 0; i < 2000; i++ {
+		if i%5 == 0 {
+			problem.Positives = append(problem.Positives, gaussianSample(rng, 2))
+		} else {
+			problem.Negatives = append(problem.Negatives, gaussianSample(rng, 2))
+		}
+	}
+	classifier := &LinearClassifier{HyperplaneNormal: Sample{V: []float64{1, 1}},
+		Kernel: LinearKernel}
+	if _, averagePrecision := PRCurve(classifier, problem); math.Abs(averagePrecision-0.2) > 0.05 {
+		t.Error("expected average precision near 0.2 but got", averagePrecision)
+	}
+}
+
+func TestPRCurveTies(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{2}}, {V: []float64{1}}},
+		Negatives: []Sample{{V: []float64{1}}, {V: []float64{0}}},
+		Kernel:    LinearKernel,
+	}
+	classifier := &LinearClassifier{HyperplaneNormal: Sample{V: []float64{1}},
+		Kernel: LinearKernel}
+	points, averagePrecision := PRCurve(classifier, problem)
+
+	expected := []PRPoint{
+		{Threshold: math.Inf(1), Recall: 0, Precision: 1},
+		{Threshold: 2, Recall: 0.5, Precision: 1},
+		{Threshold: 1, Recall: 1, Precision: 2.0 / 3},
+		{Threshold: 0, Recall: 1, Precision: 0.5},
+	}
+	if !reflect.DeepEqual(points, expected) {
+		t.Errorf("expected %v but got %v", expected, points)
+	}
+	if expectedAP := 0.5 + 0.5*2.0/3; math.Abs(averagePrecision-expectedAP) > 1e-12 {
+		t.Errorf("expected average precision %f but got %f", expectedAP, averagePrecision)
+	}
+
+	if points, averagePrecision := PRCurve(classifier, &Problem{Negatives: problem.Negatives,
+		Kernel: LinearKernel}); points != nil || !math.IsNaN(averagePrecision) {
+		t.Error("expected no curve without positives")
+	}
+}
@@ -1,16 +1,90 @@
 // Package svm implements Support Vector Machines.
 package svm
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// These errors are returned when a Problem lacks the samples it needs to be solved.
+var (
+	ErrNoPositives = errors.New("problem has no positive samples")
+	ErrNoNegatives = errors.New("problem has no negative samples")
+)
+
+// ErrDiverged indicates that a solver's solution stopped being finite.
+// Solvers wrap it in a *DivergedError, so it should be checked for with errors.Is.
+var ErrDiverged = errors.New("solver diverged")
+
+// A DivergedError is returned when a solver's solution stops being finite partway through
+// training, usually because the step size is too large or a Kernel produced NaN.
+type DivergedError struct {
+	// Step is the index (starting at 0) of the step after which the solution was not finite.
+	Step int
+}
+
+func (d *DivergedError) Error() string {
+	return fmt.Sprintf("solver diverged at step %d", d.Step)
+}
+
+// Unwrap returns ErrDiverged.
+func (d *DivergedError) Unwrap() error {
+	return ErrDiverged
+}
+
 // A Sample represents an arbitrary piece of information.
 // All samples in a given sample space must have the same number of components.
 type Sample struct {
 	V []float64
 
+	// Sparse optionally stores the components of the Sample sparsely, in which case V is unused.
+	// Such Samples are usually created with SparseSample.Sample.
+	//
+	// Sparse Samples work with LinearKernel, RadialBasisKernel, and the Kernels built on them, so
+	// they can be used with the solvers and classifiers.
+	// Transformations which work component by component, such as Standardizer, MinMaxScaler, RFE,
+	// and FourierMap, still require dense Samples.
+	Sparse *SparseSample
+
 	// UserInfo can be used by a Kernel to uniquely identify a given Sample.
 	// If a solver generates its own Samples, said Samples will have UserInfo set to 0.
 	UserInfo int
 }
 
+// NewSample creates a Sample with the given components.
+func NewSample(values ...float64) Sample {
+	return Sample{V: values}
+}
+
+// Clone creates a copy of the Sample which does not share its components.
+// The UserInfo is copied as well.
+func (s Sample) Clone() Sample {
+	if s.Sparse != nil {
+		sparse := *s.Sparse
+		sparse.Indices = append([]int(nil), sparse.Indices...)
+		sparse.Values = append([]float64(nil), sparse.Values...)
+		return Sample{Sparse: &sparse, UserInfo: s.UserInfo}
+	}
+	return Sample{V: append([]float64(nil), s.V...), UserInfo: s.UserInfo}
+}
+
+// Equal returns true if two Samples have the same number of components and corresponding
+// components differ by at most tol.
+// The UserInfo fields are not compared.
+func (s Sample) Equal(other Sample, tol float64) bool {
+	if s.Dim() != other.Dim() {
+		return false
+	}
+	for i := 0; i < s.Dim(); i++ {
+		if !(math.Abs(s.At(i)-other.At(i)) <= tol) {
+			return false
+		}
+	}
+	return true
+}
+
 // A Kernel takes two samples from a sample space and computes an inner product between them.
 type Kernel func(s1, s2 Sample) float64
 
@@ -20,4 +94,298 @@ type Problem struct {
 	Positives []Sample
 	Negatives []Sample
 	Kernel    Kernel
+
+	// PositiveWeights and NegativeWeights optionally specify how important each sample is.
+	// If non-nil, they must be the same length as Positives and Negatives, respectively, and the
+	// error margin of every sample is multiplied by its weight.
+	// If nil, every sample in the corresponding class has a weight of 1.
+	//
+	// Only SubgradientSolver and NuSubgradientSolver (and functions which use them, such as
+	// TrainAdaBoost) support weights.
+	// The other solvers panic if either slice is non-nil.
+	PositiveWeights []float64
+	NegativeWeights []float64
+
+	// Gram optionally contains the precomputed kernel products of the samples, where Gram[i][j]
+	// is the product of the i-th and j-th samples, with the positives before the negatives.
+	// Solvers which only need the products of training samples, such as SMOSolver and
+	// KernelSubgradientSolver, use it rather than calling Kernel.
+	// Kernel is still needed by the resulting classifiers to classify new samples.
+	// Problems derived from this one, such as by Split, do not inherit the Gram matrix.
+	Gram [][]float64
+}
+
+// sample returns the i-th sample in the Problem, where the positives come before the negatives.
+// It also returns 1 if the sample is positive or -1 if it is negative.
+func (p *Problem) sample(i int) (Sample, float64) {
+	if i < len(p.Positives) {
+		return p.Positives[i], 1
+	}
+	return p.Negatives[i-len(p.Positives)], -1
+}
+
+// sampleWeight returns the weight of the i-th sample, using the same ordering as sample.
+func (p *Problem) sampleWeight(i int) float64 {
+	if i < len(p.Positives) {
+		if p.PositiveWeights == nil {
+			return 1
+		}
+		return p.PositiveWeights[i]
+	}
+	if p.NegativeWeights == nil {
+		return 1
+	}
+	return p.NegativeWeights[i-len(p.Positives)]
+}
+
+// indexedKernel returns an IndexedKernel for the first len(samples) samples of the Problem, which
+// must be given in the same order as by sample.
+// It uses p.Gram if it is set, or a KernelCache for the samples otherwise.
+func (p *Problem) indexedKernel(samples []Sample) IndexedKernel {
+	if p.Gram != nil {
+		return precomputedKernel(p.Gram)
+	}
+	return NewKernelCache(p.Kernel, samples, 0)
+}
+
+// weighted returns true if the Problem has per-sample weights.
+func (p *Problem) weighted() bool {
+	return p.PositiveWeights != nil || p.NegativeWeights != nil
+}
+
+// appendSample adds the i-th sample of another Problem to p, along with its weight if weighted
+// is true.
+func (p *Problem) appendSample(src *Problem, i int, weighted bool) {
+	sample, sign := src.sample(i)
+	if sign > 0 {
+		p.Positives = append(p.Positives, sample)
+		if weighted {
+			p.PositiveWeights = append(p.PositiveWeights, src.sampleWeight(i))
+		}
+	} else {
+		p.Negatives = append(p.Negatives, sample)
+		if weighted {
+			p.NegativeWeights = append(p.NegativeWeights, src.sampleWeight(i))
+		}
+	}
+}
+
+// sampleCount returns the total number of positive and negative samples.
+func (p *Problem) sampleCount() int {
+	return len(p.Positives) + len(p.Negatives)
+}
+
+// Validate checks that a Problem can be solved.
+// It returns ErrNoPositives or ErrNoNegatives if the Problem is missing a class, or another error
+// if it has no Kernel, if its weights or Gram matrix have the wrong size, if its samples do not
+// all have the same number of components, or if any sample has a NaN or infinite component.
+func (p *Problem) Validate() error {
+	if len(p.Positives) == 0 {
+		return ErrNoPositives
+	} else if len(p.Negatives) == 0 {
+		return ErrNoNegatives
+	} else if p.Kernel == nil {
+		return errors.New("problem has no kernel")
+	}
+	if p.PositiveWeights != nil && len(p.PositiveWeights) != len(p.Positives) {
+		return fmt.Errorf("problem has %d positive weights for %d positives",
+			len(p.PositiveWeights), len(p.Positives))
+	} else if p.NegativeWeights != nil && len(p.NegativeWeights) != len(p.Negatives) {
+		return fmt.Errorf("problem has %d negative weights for %d negatives",
+			len(p.NegativeWeights), len(p.Negatives))
+	}
+	if p.Gram != nil {
+		if len(p.Gram) != p.sampleCount() {
+			return fmt.Errorf("problem has %d Gram rows for %d samples", len(p.Gram),
+				p.sampleCount())
+		}
+		for i, row := range p.Gram {
+			if len(row) != p.sampleCount() {
+				return fmt.Errorf("row %d of Gram matrix has %d entries (expected %d)", i, len(row),
+					p.sampleCount())
+			}
+		}
+	}
+	dim := p.Positives[0].Dim()
+	for i, s := range p.Positives {
+		if s.Dim() != dim {
+			return fmt.Errorf("positive %d has dimension %d (expected %d)", i, s.Dim(), dim)
+		} else if j := nonFiniteComponent(s); j >= 0 {
+			return fmt.Errorf("positive %d has non-finite component %d (%g)", i, j, s.At(j))
+		}
+	}
+	for i, s := range p.Negatives {
+		if s.Dim() != dim {
+			return fmt.Errorf("negative %d has dimension %d (expected %d)", i, s.Dim(), dim)
+		} else if j := nonFiniteComponent(s); j >= 0 {
+			return fmt.Errorf("negative %d has non-finite component %d (%g)", i, j, s.At(j))
+		}
+	}
+	return nil
+}
+
+// DropNonFinite returns a copy of the Problem without the samples that have NaN or infinite
+// components, which Validate rejects, along with the number of samples which were dropped.
+// The Kernel and the weights of the remaining samples are preserved.
+func (p *Problem) DropNonFinite() (res *Problem, dropped int) {
+	res = &Problem{Kernel: p.Kernel}
+	weighted := p.weighted()
+	for i := 0; i < p.sampleCount(); i++ {
+		if sample, _ := p.sample(i); nonFiniteComponent(sample) >= 0 {
+			dropped++
+		} else {
+			res.appendSample(p, i, weighted)
+		}
+	}
+	return
+}
+
+// nonFiniteComponent returns the index of the first NaN or infinite component of a Sample, or -1
+// if every component is finite.
+func nonFiniteComponent(s Sample) int {
+	res := -1
+	s.ForEachNonZero(func(i int, x float64) {
+		if res < 0 && (math.IsNaN(x) || math.IsInf(x, 0)) {
+			res = i
+		}
+	})
+	return res
+}
+
+// AddPositive adds a positive sample to the Problem.
+// If the Problem has per-sample weights, the sample is given a weight of 1.
+// Since the Gram matrix no longer matches the samples, it is discarded.
+func (p *Problem) AddPositive(s Sample) {
+	p.Positives = append(p.Positives, s)
+	if p.weighted() {
+		p.PositiveWeights = append(unitWeights(p.PositiveWeights, len(p.Positives)-1), 1)
+	}
+	p.Gram = nil
+}
+
+// AddNegative is like AddPositive, but it adds a negative sample.
+func (p *Problem) AddNegative(s Sample) {
+	p.Negatives = append(p.Negatives, s)
+	if p.weighted() {
+		p.NegativeWeights = append(unitWeights(p.NegativeWeights, len(p.Negatives)-1), 1)
+	}
+	p.Gram = nil
+}
+
+// Append adds the samples of another Problem to p, along with their weights.
+// If only one of the Problems has per-sample weights, the samples of the other one are given a
+// weight of 1.
+// Since the Gram matrix no longer matches the samples, it is discarded.
+// A Problem may be appended to itself, which duplicates each of its samples.
+//
+// Append returns an error, leaving p unchanged, if the Problems use different Kernels or if the
+// samples of other have a different number of components than those of p.
+// Kernels are compared as functions, so Kernels created by the same function with different
+// parameters, such as RadialBasisKernel(1) and RadialBasisKernel(2), cannot be told apart.
+func (p *Problem) Append(other *Problem) error {
+	if !sameKernel(p.Kernel, other.Kernel) {
+		return errors.New("problems have different kernels")
+	}
+	if dim, ok := p.dimension(); ok {
+		for i := 0; i < other.sampleCount(); i++ {
+			if sample, _ := other.sample(i); sample.Dim() != dim {
+				return fmt.Errorf("sample %d has dimension %d (expected %d)", i, sample.Dim(), dim)
+			}
+		}
+	}
+
+	// Copying other keeps its samples fixed while p grows, in case other is p itself.
+	src := *other
+	weighted := p.weighted() || src.weighted()
+	if weighted {
+		p.PositiveWeights = unitWeights(p.PositiveWeights, len(p.Positives))
+		p.NegativeWeights = unitWeights(p.NegativeWeights, len(p.Negatives))
+	}
+	for i := 0; i < src.sampleCount(); i++ {
+		p.appendSample(&src, i, weighted)
+	}
+	p.Gram = nil
+	return nil
+}
+
+// dimension returns the number of components of the first sample in the Problem, or false if
+// the Problem has no samples.
+func (p *Problem) dimension() (int, bool) {
+	if p.sampleCount() == 0 {
+		return 0, false
+	}
+	sample, _ := p.sample(0)
+	return sample.Dim(), true
+}
+
+// unitWeights returns weights if it is non-nil, or a slice of count weights of 1 otherwise.
+func unitWeights(weights []float64, count int) []float64 {
+	if weights != nil {
+		return weights
+	}
+	res := make([]float64, count)
+	for i := range res {
+		res[i] = 1
+	}
+	return res
+}
+
+// Split randomly partitions the samples of a Problem into a training Problem and a testing Problem.
+//
+// The positives and negatives are shuffled and split independently, so that both halves have
+// roughly the same ratio of positives to negatives.
+// The testFraction argument determines the fraction of each class which goes into the testing
+// Problem, rounded to the nearest sample.
+// Both Problems share p's Kernel.
+func (p *Problem) Split(testFraction float64, rng *rand.Rand) (train, test *Problem) {
+	train = &Problem{Kernel: p.Kernel}
+	test = &Problem{Kernel: p.Kernel}
+	weighted := p.weighted()
+	for _, class := range [][2]int{{0, len(p.Positives)}, {len(p.Positives), p.sampleCount()}} {
+		start, count := class[0], class[1]-class[0]
+		testCount := int(math.Round(testFraction * float64(count)))
+		for i, j := range rng.Perm(count) {
+			if i < testCount {
+				test.appendSample(p, start+j, weighted)
+			} else {
+				train.appendSample(p, start+j, weighted)
+			}
+		}
+	}
+	return
+}
+
+// Subsample randomly draws n samples from a Problem without replacement, which is useful for
+// quickly tuning a solver on a large Problem before training on all of it.
+//
+// The positives and negatives are sampled independently, so that the subset has roughly the same
+// ratio of positives to negatives as p.
+// As long as n is at least 2, each class present in p keeps at least one sample.
+// If n is at least the number of samples in p, the result contains every sample in a random
+// order, and if n is zero or negative, the result has no samples.
+// The result shares p's Kernel and keeps the weights of the chosen samples.
+func (p *Problem) Subsample(n int, rng *rand.Rand) *Problem {
+	res := &Problem{Kernel: p.Kernel}
+	total := p.sampleCount()
+	if n > total {
+		n = total
+	}
+	if n <= 0 {
+		return res
+	}
+	posCount := int(math.Round(float64(n) * float64(len(p.Positives)) / float64(total)))
+	if n >= 2 && len(p.Positives) > 0 && posCount == 0 {
+		posCount = 1
+	} else if n >= 2 && len(p.Negatives) > 0 && posCount == n {
+		posCount = n - 1
+	}
+
+	weighted := p.weighted()
+	for _, j := range rng.Perm(len(p.Positives))[:posCount] {
+		res.appendSample(p, j, weighted)
+	}
+	for _, j := range rng.Perm(len(p.Negatives))[:n-posCount] {
+		res.appendSample(p, len(p.Positives)+j, weighted)
+	}
+	return res
 }
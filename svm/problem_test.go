@@ -0,0 +1,439 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSampleClone(t *testing.T) {
+	original := NewSample(1, 2, 3)
+	original.UserInfo = 7
+	clone := original.Clone()
+	if !reflect.DeepEqual(clone, original) {
+		t.Fatal("clone differs from original:", clone)
+	}
+	clone.V[0] = 5
+	if original.V[0] != 1 {
+		t.Error("mutating the clone changed the original")
+	}
+	if empty := (Sample{}).Clone(); len(empty.V) != 0 {
+		t.Error("unexpected clone of empty sample:", empty)
+	}
+}
+
+func TestSampleEqual(t *testing.T) {
+	s := NewSample(1, 2, 3)
+	for _, x := range []struct {
+		other    Sample
+		tol      float64
+		expected bool
+	}{
+		{NewSample(1, 2, 3), 0, true},
+		{Sample{V: []float64{1, 2, 3}, UserInfo: 2}, 0, true},
+		{NewSample(1, 2, 3.5), 0.5, true},
+		{NewSample(1, 2, 3.5), 0.4999, false},
+		{NewSample(0.5, 2, 3), 0.5, true},
+		{NewSample(1, 2), 1, false},
+		{NewSample(1, 2, math.NaN()), 1, false},
+	} {
+		if actual := s.Equal(x.other, x.tol); actual != x.expected {
+			t.Errorf("Equal(%v, %v) should be %v", x.other.V, x.tol, x.expected)
+		}
+	}
+}
+
+func TestProblemSplit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0)
+	problem.Positives = problem.Positives[:40]
+
+	train, test := problem.Split(0.25, rng)
+	if len(train.Positives) != 30 || len(test.Positives) != 10 {
+		t.Errorf("bad positive split: %d/%d", len(train.Positives), len(test.Positives))
+	}
+	if len(train.Negatives) != 37 || len(test.Negatives) != 13 {
+		t.Errorf("bad negative split: %d/%d", len(train.Negatives), len(test.Negatives))
+	}
+	if train.Kernel == nil || test.Kernel == nil {
+		t.Error("kernel was not preserved")
+	}
+
+	seen := map[*float64]bool{}
+	for _, p := range []*Problem{train, test} {
+		for _, samples := range [][]Sample{p.Positives, p.Negatives} {
+			for _, sample := range samples {
+				if seen[&sample.V[0]] {
+					t.Fatal("sample appears twice")
+				}
+				seen[&sample.V[0]] = true
+			}
+		}
+	}
+	if len(seen) != problem.sampleCount() {
+		t.Errorf("expected %d samples but got %d", problem.sampleCount(), len(seen))
+	}
+}
+
+func TestProblemSubsample(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 300, 3, 0)
+	problem.Positives = problem.Positives[:100]
+	problem.PositiveWeights = make([]float64, 100)
+	for i := range problem.PositiveWeights {
+		problem.PositiveWeights[i] = float64(i)
+	}
+
+	sub := problem.Subsample(40, rng)
+	if len(sub.Positives) != 10 || len(sub.Negatives) != 30 {
+		t.Errorf("bad class counts: %d/%d", len(sub.Positives), len(sub.Negatives))
+	}
+	if sub.Kernel == nil {
+		t.Error("kernel was not preserved")
+	}
+	if len(sub.PositiveWeights) != len(sub.Positives) ||
+		len(sub.NegativeWeights) != len(sub.Negatives) {
+		t.Fatal("weights were not preserved")
+	}
+	for i, sample := range sub.Positives {
+		if !sample.Equal(problem.Positives[int(sub.PositiveWeights[i])], 0) {
+			t.Errorf("positive %d has the wrong weight", i)
+		}
+	}
+	seen := map[*float64]bool{}
+	for _, sample := range append(append([]Sample{}, sub.Positives...), sub.Negatives...) {
+		if seen[&sample.V[0]] {
+			t.Fatal("sample appears twice")
+		}
+		seen[&sample.V[0]] = true
+	}
+
+	if sub := problem.Subsample(3, rng); len(sub.Positives) != 1 || len(sub.Negatives) != 2 {
+		t.Errorf("bad class counts: %d/%d", len(sub.Positives), len(sub.Negatives))
+	}
+	if sub := problem.Subsample(2, rng); len(sub.Positives) != 1 || len(sub.Negatives) != 1 {
+		t.Errorf("both classes should be kept: %d/%d", len(sub.Positives), len(sub.Negatives))
+	}
+
+	all := problem.Subsample(1000, rng)
+	if len(all.Positives) != 100 || len(all.Negatives) != 300 {
+		t.Errorf("bad class counts: %d/%d", len(all.Positives), len(all.Negatives))
+	}
+	inOrder := true
+	for i, sample := range all.Negatives {
+		if &sample.V[0] != &problem.Negatives[i].V[0] {
+			inOrder = false
+		}
+	}
+	if inOrder {
+		t.Error("samples were not shuffled")
+	}
+
+	for _, n := range []int{0, -5} {
+		if sub := problem.Subsample(n, rng); sub.sampleCount() != 0 || sub.Kernel == nil {
+			t.Errorf("n=%d: expected an empty problem but got %d samples", n, sub.sampleCount())
+		}
+	}
+	empty := &Problem{Kernel: LinearKernel}
+	if sub := empty.Subsample(10, rng); sub.sampleCount() != 0 || sub.Kernel == nil {
+		t.Errorf("expected an empty problem but got %d samples", sub.sampleCount())
+	}
+}
+
+func TestProblemValidate(t *testing.T) {
+	valid := func() *Problem {
+		return &Problem{
+			Positives: []Sample{{V: []float64{1, 2}}, {V: []float64{2, 1}}},
+			Negatives: []Sample{{V: []float64{-1, -2}}},
+			Kernel:    LinearKernel,
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Error("unexpected error:", err)
+	}
+
+	noPositives := valid()
+	noPositives.Positives = nil
+	noNegatives := valid()
+	noNegatives.Negatives = nil
+	noKernel := valid()
+	noKernel.Kernel = nil
+	badPositive := valid()
+	badPositive.Positives[1].V = []float64{1}
+	badNegative := valid()
+	badNegative.Negatives[0].V = []float64{1, 2, 3}
+	badPositiveWeights := valid()
+	badPositiveWeights.PositiveWeights = []float64{1}
+	badNegativeWeights := valid()
+	badNegativeWeights.NegativeWeights = []float64{1, 2}
+	badGramRows := valid()
+	badGramRows.Gram = [][]float64{{1}}
+	badGramRow := valid()
+	badGramRow.Gram = make([][]float64, badGramRow.sampleCount())
+	for i := range badGramRow.Gram {
+		badGramRow.Gram[i] = make([]float64, badGramRow.sampleCount()-i%2)
+	}
+
+	for name, problem := range map[string]*Problem{
+		"no positives": noPositives,
+		"no negatives": noNegatives,
+		"no kernel":    noKernel,
+		"bad positive": badPositive,
+		"bad negative": badNegative,
+
+		"bad positive weights": badPositiveWeights,
+		"bad negative weights": badNegativeWeights,
+		"bad Gram rows":        badGramRows,
+		"bad Gram row":         badGramRow,
+	} {
+		if problem.Validate() == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		solver := &SubgradientSolver{Tradeoff: 0.1, Steps: 1, StepSize: 0.1}
+		if c, err := solver.SolveE(problem); err == nil || c != nil {
+			t.Errorf("%s: expected SolveE to fail", name)
+		}
+	}
+}
+
+func TestProblemNonFinite(t *testing.T) {
+	for _, x := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		for _, positive := range []bool{true, false} {
+			problem := &Problem{
+				Positives: []Sample{NewSample(1, 2), NewSample(2, 1)},
+				Negatives: []Sample{NewSample(-1, -2), NewSample(-2, -1)},
+				Kernel:    LinearKernel,
+			}
+			bad := problem.Negatives
+			if positive {
+				bad = problem.Positives
+			}
+			bad[1].V[1] = x
+			err := problem.Validate()
+			if err == nil {
+				t.Errorf("%v (positive=%v): expected error", x, positive)
+				continue
+			}
+			class := "negative"
+			if positive {
+				class = "positive"
+			}
+			if msg := err.Error(); !strings.Contains(msg, class+" 1") ||
+				!strings.Contains(msg, "component 1") {
+				t.Errorf("error does not identify the sample: %s", msg)
+			}
+
+			filtered, dropped := problem.DropNonFinite()
+			if dropped != 1 || filtered.sampleCount() != 3 {
+				t.Errorf("%v (positive=%v): dropped %d samples", x, positive, dropped)
+			} else if err := filtered.Validate(); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+
+	weighted := &Problem{
+		Positives:       []Sample{NewSample(math.NaN()), NewSample(1), NewSample(2)},
+		Negatives:       []Sample{NewSample(-1)},
+		Kernel:          LinearKernel,
+		PositiveWeights: []float64{1, 2, 3},
+	}
+	filtered, _ := weighted.DropNonFinite()
+	if !reflect.DeepEqual(filtered.PositiveWeights, []float64{2, 3}) ||
+		!reflect.DeepEqual(filtered.NegativeWeights, []float64{1}) {
+		t.Error("unexpected weights:", filtered.PositiveWeights, filtered.NegativeWeights)
+	}
+}
+
+func TestProblemGram(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	explicit := randomLinearProblem(rng, 20, 3, 0.1)
+	precomputed := &Problem{
+		Positives: explicit.Positives,
+		Negatives: explicit.Negatives,
+		Kernel: func(s1, s2 Sample) float64 {
+			panic("kernel should not be called")
+		},
+		Gram: make([][]float64, explicit.sampleCount()),
+	}
+	for i := range precomputed.Gram {
+		s1, _ := explicit.sample(i)
+		precomputed.Gram[i] = make([]float64, explicit.sampleCount())
+		for j := range precomputed.Gram[i] {
+			s2, _ := explicit.sample(j)
+			for k, x := range s1.V {
+				precomputed.Gram[i][j] += x * s2.V[k]
+			}
+		}
+	}
+	if err := precomputed.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	sameSolution := func(c1, c2 *CombinationClassifier) bool {
+		return c1.Threshold == c2.Threshold &&
+			reflect.DeepEqual(c1.Coefficients, c2.Coefficients) &&
+			reflect.DeepEqual(c1.SupportVectors, c2.SupportVectors)
+	}
+	smo := &SMOSolver{Tradeoff: 0.01}
+	if !sameSolution(smo.Solve(precomputed), smo.Solve(explicit)) {
+		t.Error("SMO solutions differ")
+	}
+	subgradient := &KernelSubgradientSolver{Tradeoff: 0.01, Steps: 50, StepSize: 0.01}
+	if !sameSolution(subgradient.Solve(precomputed), subgradient.Solve(explicit)) {
+		t.Error("sub-gradient solutions differ")
+	}
+}
+
+func TestProblemSolveEmpty(t *testing.T) {
+	solver := &SubgradientSolver{Tradeoff: 0.1, Steps: 1, StepSize: 0.1}
+	samples := []Sample{{V: []float64{1, 2}}}
+	problems := []struct {
+		problem  *Problem
+		expected error
+	}{
+		{&Problem{Negatives: samples, Kernel: LinearKernel}, ErrNoPositives},
+		{&Problem{Positives: samples, Kernel: LinearKernel}, ErrNoNegatives},
+		{&Problem{Kernel: LinearKernel}, ErrNoPositives},
+	}
+	for i, x := range problems {
+		func() {
+			defer func() {
+				if err := recover(); err != nil {
+					t.Errorf("problem %d: unexpected panic: %v", i, err)
+				}
+			}()
+			if _, err := solver.SolveE(x.problem); err != x.expected {
+				t.Errorf("problem %d: expected %v but got %v", i, x.expected, err)
+			}
+		}()
+	}
+}
+
+func TestProblemSampleWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	weighted := randomLinearProblem(rng, 20, 3, 0)
+	weighted.PositiveWeights = make([]float64, len(weighted.Positives))
+	weighted.NegativeWeights = make([]float64, len(weighted.Negatives))
+	duplicated := &Problem{Kernel: weighted.Kernel, Negatives: weighted.Negatives}
+	for i, sample := range weighted.Positives {
+		weighted.PositiveWeights[i] = 1
+		duplicated.Positives = append(duplicated.Positives, sample)
+		if i%3 == 0 {
+			weighted.PositiveWeights[i] = 2
+			duplicated.Positives = append(duplicated.Positives, sample)
+		}
+	}
+	for i := range weighted.Negatives {
+		weighted.NegativeWeights[i] = 1
+	}
+
+	for _, kernel := range []Kernel{LinearKernel, PolynomialKernel(1, 2)} {
+		weighted.Kernel, duplicated.Kernel = kernel, kernel
+		solver := &SubgradientSolver{Tradeoff: 0.01, Steps: 100, StepSize: 0.001}
+		expected := solver.Solve(duplicated)
+		actual := solver.Solve(weighted)
+		if math.Abs(actual.Threshold-expected.Threshold) > 1e-9 {
+			t.Errorf("threshold should be %f but got %f", expected.Threshold, actual.Threshold)
+		}
+		for i, x := range expected.HyperplaneNormal.V {
+			if math.Abs(actual.HyperplaneNormal.V[i]-x) > 1e-9 {
+				t.Errorf("component %d should be %f but got %f", i, x,
+					actual.HyperplaneNormal.V[i])
+			}
+		}
+	}
+
+	train, test := weighted.Split(0.25, rng)
+	for _, p := range []*Problem{train, test} {
+		if err := p.Validate(); err != nil {
+			t.Error("split produced invalid problem:", err)
+		}
+	}
+	var weightSum float64
+	for _, p := range []*Problem{train, test} {
+		for _, w := range p.PositiveWeights {
+			weightSum += w
+		}
+	}
+	if weightSum != float64(len(duplicated.Positives)) {
+		t.Error("split lost weights: total positive weight is", weightSum)
+	}
+}
+
+func TestProblemAppend(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 10, 3, 0)
+	problem.Positives = problem.Positives[:8]
+	other := randomLinearProblem(rng, 5, 3, 0)
+	other.PositiveWeights = []float64{1, 2, 3, 4, 5}
+	other.NegativeWeights = []float64{1, 1, 1, 1, 2}
+	if err := problem.Append(other); err != nil {
+		t.Fatal(err)
+	}
+	if len(problem.Positives) != 13 || len(problem.Negatives) != 15 {
+		t.Errorf("unexpected sizes: %d/%d", len(problem.Positives), len(problem.Negatives))
+	}
+	if err := problem.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if problem.PositiveWeights[7] != 1 || problem.PositiveWeights[12] != 5 {
+		t.Error("unexpected positive weights:", problem.PositiveWeights)
+	}
+
+	problem.AddPositive(NewSample(1, 2, 3))
+	problem.AddNegative(NewSample(-1, -2, -3))
+	if len(problem.Positives) != 14 || len(problem.Negatives) != 16 {
+		t.Errorf("unexpected sizes: %d/%d", len(problem.Positives), len(problem.Negatives))
+	}
+	if err := problem.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	wrongDimension := randomLinearProblem(rng, 5, 2, 0)
+	wrongKernel := randomLinearProblem(rng, 5, 3, 0)
+	wrongKernel.Kernel = RadialBasisKernel(1)
+	for name, x := range map[string]*Problem{"dimension": wrongDimension, "kernel": wrongKernel} {
+		if err := problem.Append(x); err == nil {
+			t.Errorf("expected %s mismatch error", name)
+		}
+	}
+	if len(problem.Positives) != 14 || len(problem.Negatives) != 16 {
+		t.Error("failed Append changed the problem")
+	}
+
+	empty := &Problem{Kernel: LinearKernel}
+	empty.AddPositive(NewSample(1))
+	negative := &Problem{Kernel: LinearKernel, Negatives: []Sample{NewSample(2)}}
+	if err := empty.Append(negative); err != nil {
+		t.Error(err)
+	} else if empty.PositiveWeights != nil || len(empty.Negatives) != 1 {
+		t.Error("unexpected result of appending to unweighted problem")
+	}
+}
+
+func TestProblemAppendSelf(t *testing.T) {
+	problem := &Problem{
+		Positives:       []Sample{NewSample(1, 2), NewSample(2, 1)},
+		Negatives:       []Sample{NewSample(-1, -2)},
+		PositiveWeights: []float64{1, 2},
+		Kernel:          LinearKernel,
+	}
+	if err := problem.Append(problem); err != nil {
+		t.Fatal(err)
+	}
+	expectedPositives := []Sample{
+		NewSample(1, 2), NewSample(2, 1), NewSample(1, 2), NewSample(2, 1),
+	}
+	expectedNegatives := []Sample{NewSample(-1, -2), NewSample(-1, -2)}
+	if !reflect.DeepEqual(problem.Positives, expectedPositives) ||
+		!reflect.DeepEqual(problem.Negatives, expectedNegatives) {
+		t.Errorf("unexpected samples: %v %v", problem.Positives, problem.Negatives)
+	}
+	if !reflect.DeepEqual(problem.PositiveWeights, []float64{1, 2, 1, 2}) ||
+		!reflect.DeepEqual(problem.NegativeWeights, []float64{1, 1}) {
+		t.Errorf("unexpected weights: %v %v", problem.PositiveWeights, problem.NegativeWeights)
+	}
+}
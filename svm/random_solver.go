@@ -18,7 +18,7 @@ func RandomlySolveLinear(p *Problem, numGuesses int, maxEntry float64) *LinearCl
 	var bestMagnitude float64
 
 	for i := 0; i < numGuesses; i++ {
-		guess := randomSample(len(p.Positives[0].V), maxEntry)
+		guess := randomSample(p.Positives[0].Dim(), maxEntry)
 		mag := p.Kernel(guess, guess)
 		threshold := idealThresholdForGuess(guess, p)
 
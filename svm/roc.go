@@ -0,0 +1,73 @@
+package svm
+
+import (
+	"math"
+	"sort"
+)
+
+// An ROCPoint is a point on a receiver operating characteristic curve.
+type ROCPoint struct {
+	// Threshold is the smallest decision value which is classified as positive at this point.
+	Threshold float64
+
+	FalsePositiveRate float64
+	TruePositiveRate  float64
+}
+
+// ROC computes the receiver operating characteristic curve of a Classifier on a Problem, along
+// with the area under the curve (AUC).
+//
+// The curve is generated by sweeping a threshold over the decision values (as returned by
+// c.Rating) from highest to lowest.
+// It starts at (0, 0), where no samples are positive, and ends at (1, 1), where every sample is.
+// Samples with equal decision values are always classified together, so they form a single step
+// in the curve.
+//
+// The AUC is computed with the trapezoidal rule and is always in [0, 1].
+// It is the probability that a random positive has a higher decision value than a random
+// negative, counting ties as half.
+// If the Problem has no positives or no negatives, the curve is nil and the AUC is NaN.
+func ROC(c Classifier, p *Problem) ([]ROCPoint, float64) {
+	if len(p.Positives) == 0 || len(p.Negatives) == 0 {
+		return nil, math.NaN()
+	}
+
+	type scoredSample struct {
+		value    float64
+		positive bool
+	}
+	scores := make([]scoredSample, p.sampleCount())
+	for i := range scores {
+		sample, sign := p.sample(i)
+		scores[i] = scoredSample{c.Rating(sample), sign > 0}
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].value > scores[j].value
+	})
+
+	numPos, numNeg := float64(len(p.Positives)), float64(len(p.Negatives))
+	points := []ROCPoint{{Threshold: math.Inf(1)}}
+	var auc float64
+	var truePos, falsePos int
+	for i := 0; i < len(scores); {
+		threshold := scores[i].value
+		for ; i < len(scores) && scores[i].value == threshold; i++ {
+			if scores[i].positive {
+				truePos++
+			} else {
+				falsePos++
+			}
+		}
+		last := points[len(points)-1]
+		point := ROCPoint{
+			Threshold:         threshold,
+			FalsePositiveRate: float64(falsePos) / numNeg,
+			TruePositiveRate:  float64(truePos) / numPos,
+		}
+		auc += (point.FalsePositiveRate - last.FalsePositiveRate) *
+			(point.TruePositiveRate + last.TruePositiveRate) / 2
+		points = append(points, point)
+	}
+
+	return points, auc
+}
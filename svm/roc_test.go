@@ -0,0 +1,67 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestROCSeparable(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+	points, auc := ROC(solver.Solve(problem), problem)
+	if auc != 1 {
+		t.Error("expected AUC 1 but got", auc)
+	}
+	first, last := points[0], points[len(points)-1]
+	if first.FalsePositiveRate != 0 || first.TruePositiveRate != 0 {
+		t.Error("bad first point:", first)
+	}
+	if last.FalsePositiveRate != 1 || last.TruePositiveRate != 1 {
+		t.Error("bad last point:", last)
+	}
+}
+
+func TestROCRandomLabels(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &Problem{Kernel: LinearKernel}
+	for i := 0; i < 1000; i++ {
+		problem.Positives = append(problem.Positives, gaussianSample(rng, 2))
+		problem.Negatives = append(problem.Negatives, gaussianSample(rng, 2))
+	}
+	classifier := &LinearClassifier{HyperplaneNormal: Sample{V: []float64{1, 1}},
+		Kernel: LinearKernel}
+	if _, auc := ROC(classifier, problem); math.Abs(auc-0.5) > 0.05 {
+		t.Error("expected AUC near 0.5 but got", auc)
+	}
+}
+
+func TestROCTies(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{2}}, {V: []float64{1}}},
+		Negatives: []Sample{{V: []float64{1}}, {V: []float64{0}}},
+		Kernel:    LinearKernel,
+	}
+	classifier := &LinearClassifier{HyperplaneNormal: Sample{V: []float64{1}},
+		Kernel: LinearKernel}
+	points, auc := ROC(classifier, problem)
+
+	expected := []ROCPoint{
+		{math.Inf(1), 0, 0},
+		{2, 0, 0.5},
+		{1, 0.5, 1},
+		{0, 1, 1},
+	}
+	if len(points) != len(expected) {
+		t.Fatal("unexpected points:", points)
+	}
+	for i, x := range expected {
+		if points[i] != x {
+			t.Errorf("point %d should be %v but got %v", i, x, points[i])
+		}
+	}
+	if auc != 0.875 {
+		t.Error("expected AUC 0.875 but got", auc)
+	}
+}
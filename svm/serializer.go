@@ -0,0 +1,88 @@
+package svm
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type linearClassifierData struct {
+	Normal    []float64 `json:"normal"`
+	Threshold float64   `json:"threshold"`
+	Kernel    string    `json:"kernel"`
+}
+
+// MarshalJSON encodes the classifier as JSON.
+// The kernel is encoded by name, so c.KernelName must be set unless c.Kernel is LinearKernel.
+func (c *LinearClassifier) MarshalJSON() ([]byte, error) {
+	data, err := c.serializerData()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON decodes a classifier which was encoded with MarshalJSON.
+// It fails if the kernel name is not registered.
+func (c *LinearClassifier) UnmarshalJSON(d []byte) error {
+	var data linearClassifierData
+	if err := json.Unmarshal(d, &data); err != nil {
+		return err
+	}
+	return c.setSerializerData(&data)
+}
+
+// GobEncode encodes the classifier in the gob format.
+// Like MarshalJSON, this requires c.KernelName to be set unless c.Kernel is LinearKernel.
+func (c *LinearClassifier) GobEncode() ([]byte, error) {
+	data, err := c.serializerData()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes a classifier which was encoded with GobEncode.
+func (c *LinearClassifier) GobDecode(d []byte) error {
+	var data linearClassifierData
+	if err := gob.NewDecoder(bytes.NewReader(d)).Decode(&data); err != nil {
+		return err
+	}
+	return c.setSerializerData(&data)
+}
+
+func (c *LinearClassifier) serializerData() (*linearClassifierData, error) {
+	name := c.KernelName
+	if name == "" {
+		if !isLinearKernel(c.Kernel) {
+			return nil, errors.New("cannot serialize classifier without a KernelName")
+		}
+		name = "linear"
+	}
+	return &linearClassifierData{
+		Normal:    c.HyperplaneNormal.V,
+		Threshold: c.Threshold,
+		Kernel:    name,
+	}, nil
+}
+
+func (c *LinearClassifier) setSerializerData(data *linearClassifierData) error {
+	if len(data.Normal) == 0 {
+		return errors.New("cannot deserialize classifier: empty normal vector")
+	}
+	kernel, err := NamedKernel(data.Kernel)
+	if err != nil {
+		return fmt.Errorf("cannot deserialize classifier: %s", err)
+	}
+	c.HyperplaneNormal = Sample{V: data.Normal}
+	c.Threshold = data.Threshold
+	c.Kernel = kernel
+	c.KernelName = data.Kernel
+	return nil
+}
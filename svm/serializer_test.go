@@ -0,0 +1,131 @@
+package svm
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestLinearClassifierJSON(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	classifiers := []*LinearClassifier{
+		{
+			HyperplaneNormal: Sample{V: []float64{1, -2, 3}},
+			Threshold:        -0.5,
+			Kernel:           LinearKernel,
+		},
+		{
+			HyperplaneNormal: Sample{V: []float64{0.5, 0.25, -1}},
+			Threshold:        0.3,
+			Kernel:           RadialBasisKernel(0.5),
+			KernelName:       "rbf:0.5",
+		},
+		{
+			HyperplaneNormal: Sample{V: []float64{-0.5, 2, 0.75}},
+			Threshold:        1.5,
+			Kernel:           GeneralPolynomialKernel(2, 1, 0.5),
+			KernelName:       "polynomial:2,1,0.5",
+		},
+	}
+	for i, classifier := range classifiers {
+		data, err := json.Marshal(classifier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded LinearClassifier
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 10; j++ {
+			sample := gaussianSample(rng, 3)
+			if decoded.Rating(sample) != classifier.Rating(sample) {
+				t.Errorf("classifier %d: rating mismatch", i)
+			}
+		}
+	}
+}
+
+func TestLinearClassifierJSONErrors(t *testing.T) {
+	classifier := &LinearClassifier{
+		HyperplaneNormal: Sample{V: []float64{1}},
+		Kernel:           RadialBasisKernel(0.5),
+	}
+	if _, err := json.Marshal(classifier); err == nil {
+		t.Error("expected error for unnamed kernel")
+	}
+
+	var decoded LinearClassifier
+	data := []byte(`{"normal":[1],"threshold":0,"kernel":"unknown:3"}`)
+	if err := json.Unmarshal(data, &decoded); err == nil {
+		t.Error("expected error for unknown kernel")
+	}
+}
+
+func TestLinearClassifierGob(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 100, 4, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.001, Steps: 100, StepSize: 0.001}
+	classifier := solver.Solve(problem)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(classifier); err != nil {
+		t.Fatal(err)
+	}
+	var decoded LinearClassifier
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	heldOut := randomLinearProblem(rng, 50, 4, 0)
+	for _, samples := range [][]Sample{heldOut.Positives, heldOut.Negatives} {
+		for _, sample := range samples {
+			if decoded.Classify(sample) != classifier.Classify(sample) {
+				t.Fatal("classification mismatch")
+			}
+		}
+	}
+}
+
+func TestLinearClassifierGobErrors(t *testing.T) {
+	for _, data := range []*linearClassifierData{
+		{Normal: []float64{1}, Kernel: "unknown"},
+		{Kernel: "linear"},
+	} {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(data); err != nil {
+			t.Fatal(err)
+		}
+		var decoded LinearClassifier
+		if err := decoded.GobDecode(buf.Bytes()); err == nil {
+			t.Errorf("expected error for %v", data)
+		}
+	}
+}
+
+func TestNamedKernel(t *testing.T) {
+	s1, s2 := Sample{V: []float64{1, 2}}, Sample{V: []float64{-1, 0.5}}
+	expected := map[string]float64{
+		"linear":             LinearKernel(s1, s2),
+		"polynomial:1,2":     PolynomialKernel(1, 2)(s1, s2),
+		"polynomial:3,1,0.5": GeneralPolynomialKernel(3, 1, 0.5)(s1, s2),
+		"rbf:0.5":            RadialBasisKernel(0.5)(s1, s2),
+		"sigmoid:0.5,1":      SigmoidKernel(0.5, 1)(s1, s2),
+	}
+	for name, value := range expected {
+		kernel, err := NamedKernel(name)
+		if err != nil {
+			t.Error(err)
+		} else if kernel(s1, s2) != value {
+			t.Errorf("unexpected value for %s", name)
+		}
+	}
+
+	for _, name := range []string{"rbf", "rbf:x", "linear:1", "polynomial:1,0", "polynomial:1,2.5",
+		"polynomial:0,1,1", "polynomial:2.5,1,1", "polynomial:1,1,1,1", "foo"} {
+		if _, err := NamedKernel(name); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
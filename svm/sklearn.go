@@ -0,0 +1,87 @@
+package svm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// sklearnLinearSVCData stores the fitted attributes of a scikit-learn LinearSVC.
+// Each attribute may be stored either as exported by numpy (a matrix for coef_ and an array for
+// intercept_) or flattened.
+type sklearnLinearSVCData struct {
+	Coef      json.RawMessage `json:"coef_"`
+	Intercept json.RawMessage `json:"intercept_"`
+}
+
+// LoadSklearnLinearSVC reads a binary linear SVM trained with scikit-learn, such as a LinearSVC
+// or an SVC with kernel="linear", from a JSON object containing its coef_ and intercept_
+// attributes.
+// Such an object can be written from Python with
+//
+//	json.dump({"coef_": clf.coef_.tolist(), "intercept_": clf.intercept_.tolist()}, f)
+//
+// The coef_ attribute may be a single row, as scikit-learn stores it for binary problems, or a
+// flat array, and intercept_ may be a one-element array or a number.
+//
+// The resulting classifier uses LinearKernel, with coef_ as the normal and intercept_ as the
+// threshold.
+// Since scikit-learn's decision_function is X*coef_ + intercept_, DecisionValue returns exactly
+// the same value, and a sample is positive when it would be assigned to clf.classes_[1].
+// Samples must have their features in the same order as the columns of coef_, and any
+// preprocessing (such as a StandardScaler) must still be applied before classifying them.
+//
+// An error is returned for multiclass models, which have more than one row of coefficients.
+func LoadSklearnLinearSVC(r io.Reader) (*LinearClassifier, error) {
+	var data sklearnLinearSVCData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	if data.Coef == nil {
+		return nil, errors.New("missing coef_")
+	} else if data.Intercept == nil {
+		return nil, errors.New("missing intercept_")
+	}
+
+	normal, err := sklearnVector(data.Coef)
+	if err != nil {
+		return nil, fmt.Errorf("coef_: %w", err)
+	} else if len(normal) == 0 {
+		return nil, errors.New("coef_ is empty")
+	}
+	intercept, err := sklearnVector(data.Intercept)
+	if err != nil {
+		return nil, fmt.Errorf("intercept_: %w", err)
+	} else if len(intercept) != 1 {
+		return nil, fmt.Errorf("expected 1 intercept but got %d", len(intercept))
+	}
+
+	return &LinearClassifier{
+		HyperplaneNormal: Sample{V: normal},
+		Threshold:        intercept[0],
+		Kernel:           LinearKernel,
+		KernelName:       "linear",
+	}, nil
+}
+
+// sklearnVector decodes a number, an array of numbers, or a matrix with a single row.
+func sklearnVector(raw json.RawMessage) ([]float64, error) {
+	var matrix [][]float64
+	if err := json.Unmarshal(raw, &matrix); err == nil {
+		if len(matrix) != 1 {
+			return nil, fmt.Errorf("expected 1 row but got %d (multiclass models are not "+
+				"supported)", len(matrix))
+		}
+		return matrix[0], nil
+	}
+	var vector []float64
+	if err := json.Unmarshal(raw, &vector); err == nil {
+		return vector, nil
+	}
+	var number float64
+	if err := json.Unmarshal(raw, &number); err != nil {
+		return nil, errors.New("expected a number, an array, or a matrix")
+	}
+	return []float64{number}, nil
+}
@@ -0,0 +1,46 @@
+package svm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadSklearnLinearSVC(t *testing.T) {
+	for _, data := range []string{
+		`{"coef_": [[0.5, -2, 1]], "intercept_": [-0.25]}`,
+		`{"coef_": [0.5, -2, 1], "intercept_": -0.25}`,
+	} {
+		classifier, err := LoadSklearnLinearSVC(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// decision_function([[2, 1, 3]]) is 1 - 2 + 3 - 0.25 = 1.75.
+		if value := classifier.DecisionValue(NewSample(2, 1, 3)); value != 1.75 {
+			t.Errorf("expected decision value 1.75 but got %f", value)
+		}
+		if !classifier.Classify(NewSample(2, 1, 3)) {
+			t.Error("expected a positive classification")
+		}
+		if classifier.Classify(NewSample(0, 1, 0)) {
+			t.Error("expected a negative classification")
+		}
+		if _, err := classifier.MarshalJSON(); err != nil {
+			t.Error("classifier should be serializable:", err)
+		}
+	}
+
+	for _, data := range []string{
+		`{"coef_": [[1, 2], [3, 4]], "intercept_": [0, 1]}`,
+		`{"coef_": [[]], "intercept_": [0]}`,
+		`{"coef_": [[1, 2]], "intercept_": [0, 1]}`,
+		`{"coef_": [[1, 2]]}`,
+		`{"intercept_": [0]}`,
+		`{"coef_": "1, 2", "intercept_": [0]}`,
+		`{"coef_": null, "intercept_": [0]}`,
+		`not json`,
+	} {
+		if _, err := LoadSklearnLinearSVC(strings.NewReader(data)); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
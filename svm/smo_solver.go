@@ -0,0 +1,423 @@
+package svm
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	defaultSMOTolerance = 1e-3
+	smoStepEpsilon      = 1e-12
+)
+
+// An SMOSolver solves Problems using Sequential Minimal Optimization.
+//
+// SMO works on the dual form of the soft-margin problem, repeatedly picking a pair of Lagrange
+// multipliers and optimizing them jointly while holding the rest fixed.
+// Since the solution is expressed as a combination of support vectors, this works with any
+// positive semi-definite Kernel, not just linear ones.
+type SMOSolver struct {
+	// Tradeoff determines how important a wide separation margin is.
+	// It has the same meaning as GradientDescentSolver's Tradeoff.
+	Tradeoff float64
+
+	// Tolerance is the amount by which a multiplier may violate the KKT conditions and still be
+	// considered optimal.
+	// If this is zero, a default of 1e-3 is used.
+	Tolerance float64
+
+	// MaxIterations is the maximum number of passes the solver may make over the multipliers.
+	// If this is zero, the solver runs until no multiplier violates the KKT conditions.
+	MaxIterations int
+
+	// GapTolerance, if non-zero, causes the solver to stop once the duality gap (see
+	// SolveWithGap) falls below GapTolerance.
+	// The gap is checked after every pass over the multipliers.
+	// Unlike Tolerance, this bounds how far the solution's objective is from the optimum.
+	// Since the gap depends on the errors of every multiplier, checking it undoes shrinking
+	// after every pass.
+	GapTolerance float64
+
+	// Shrinking enables the shrinking heuristic.
+	// It is on by default for solvers created with NewSMOSolver, but since it is a bool, it is
+	// off for an SMOSolver created as a struct literal unless it is set explicitly.
+	//
+	// After every pass over all of the multipliers, the solver temporarily sets aside the
+	// multipliers which are at a bound and satisfy the KKT conditions with room to spare, since
+	// they are unlikely to change.
+	// The remaining passes neither examine them nor update their errors, which saves time on
+	// Problems with many bounded support vectors when the Kernel is expensive.
+	// Before concluding that the solution is optimal, the solver recomputes the errors of the
+	// multipliers it set aside and examines them again, after which it stops shrinking.
+	Shrinking bool
+
+	// MaxRows, if non-zero, limits how many rows of the Gram matrix the solver stores at once.
+	// Rows are computed when they are first needed, and the least recently used row is evicted
+	// when MaxRows rows are stored (see NewKernelCacheRows).
+	// If this is zero, the entire Gram matrix is computed up front, which may not fit in memory
+	// for large Problems.
+	// This is ignored if the Problem has a Gram matrix.
+	MaxRows int
+}
+
+// NewSMOSolver creates an SMOSolver with the given Tradeoff and the default settings, including
+// Shrinking.
+func NewSMOSolver(tradeoff float64) *SMOSolver {
+	return &SMOSolver{Tradeoff: tradeoff, Shrinking: true}
+}
+
+// Solve runs SMO on a Problem.
+// It panics if the Problem has per-sample weights, which SMOSolver does not support.
+func (s *SMOSolver) Solve(p *Problem) *CombinationClassifier {
+	res, _ := s.SolveWithGap(p)
+	return res
+}
+
+// SolveWithGap is like Solve, but it also returns the duality gap of the solution.
+//
+// The duality gap is the primal objective, |w|^2/2 + C*sum(max(0, 1-y_i*f(x_i))), minus the
+// dual objective, sum(alpha_i) - |w|^2/2, where C = 1/(2*Tradeoff*n) is the bound on every
+// multiplier and f is the decision function of the solution.
+// It is never negative and reaches zero at the optimum, so it bounds how suboptimal the
+// solution is.
+func (s *SMOSolver) SolveWithGap(p *Problem) (*CombinationClassifier, float64) {
+	iter := s.solve(p)
+	return iter.Solution(p), iter.dualityGap()
+}
+
+// SolveWithCacheStats is like Solve, but it also returns the hit and miss counts of the kernel
+// cache, which can be used to tune MaxRows.
+// The counts are zero unless MaxRows is set.
+func (s *SMOSolver) SolveWithCacheStats(p *Problem) (*CombinationClassifier, KernelCacheStats) {
+	iter := s.solve(p)
+	var stats KernelCacheStats
+	if cache, ok := iter.kernel.(*KernelCache); ok {
+		stats = cache.Stats()
+	}
+	return iter.Solution(p), stats
+}
+
+// solve runs SMO on a Problem and returns the final state, with every multiplier active.
+func (s *SMOSolver) solve(p *Problem) *smoIterator {
+	if p.weighted() {
+		panic("SMOSolver does not support sample weights")
+	}
+	samples := make([]Sample, 0, p.sampleCount())
+	samples = append(samples, p.Positives...)
+	samples = append(samples, p.Negatives...)
+	var kernel IndexedKernel
+	if s.MaxRows != 0 && p.Gram == nil {
+		kernel = NewKernelCacheRows(p.Kernel, samples, s.MaxRows)
+	} else {
+		kernel = p.indexedKernel(samples)
+	}
+	iter := newSMOIterator(p, kernel, s.maxCoefficient(p), s.tolerance())
+
+	examineAll := true
+	shrinking := s.Shrinking
+	for i := 0; s.MaxIterations == 0 || i < s.MaxIterations; i++ {
+		var numChanged int
+		for _, j := range iter.active {
+			if examineAll || !iter.atBound(j) {
+				if iter.examine(j) {
+					numChanged++
+				}
+			}
+		}
+		if s.GapTolerance != 0 {
+			iter.unshrink()
+			if iter.dualityGap() < s.GapTolerance {
+				break
+			}
+		}
+		if examineAll {
+			if numChanged == 0 {
+				if iter.unshrink() {
+					// The solution is nearly optimal, so shrinking again would mostly cost
+					// extra passes over the multipliers it set aside.
+					shrinking = false
+					continue
+				}
+				break
+			}
+			if shrinking {
+				iter.shrink()
+			}
+			examineAll = false
+		} else if numChanged == 0 {
+			examineAll = true
+		}
+	}
+
+	iter.unshrink()
+	return iter
+}
+
+func (s *SMOSolver) maxCoefficient(p *Problem) float64 {
+	sampleCount := float64(len(p.Positives) + len(p.Negatives))
+	return 1 / (2 * s.Tradeoff * sampleCount)
+}
+
+func (s *SMOSolver) tolerance() float64 {
+	if s.Tolerance == 0 {
+		return defaultSMOTolerance
+	}
+	return s.Tolerance
+}
+
+// smoIterator stores the state of the SMO algorithm.
+//
+// The decision function is sum(alpha_i*y_i*K(x_i, x)) - bias, following the notation from Platt's
+// original paper.
+//
+// Only the multipliers whose indices are in active are examined and optimized, and only their
+// errors are kept up to date.
+type smoIterator struct {
+	kernel    IndexedKernel
+	signs     []float64
+	alphas    []float64
+	errors    []float64
+	bias      float64
+	maxCoeff  float64
+	tolerance float64
+	active    []int
+}
+
+// newSMOIterator creates an smoIterator for a Problem, given an IndexedKernel for its samples.
+func newSMOIterator(p *Problem, kernel IndexedKernel, maxCoeff, tolerance float64) *smoIterator {
+	n := p.sampleCount()
+	res := &smoIterator{
+		kernel:    kernel,
+		signs:     make([]float64, n),
+		alphas:    make([]float64, n),
+		errors:    make([]float64, n),
+		maxCoeff:  maxCoeff,
+		tolerance: tolerance,
+		active:    make([]int, n),
+	}
+
+	for i := 0; i < n; i++ {
+		res.active[i] = i
+		if i < len(p.Positives) {
+			res.signs[i] = 1
+		} else {
+			res.signs[i] = -1
+		}
+		// With every alpha at zero, the output is zero and the error is -y.
+		res.errors[i] = -res.signs[i]
+	}
+
+	return res
+}
+
+// Solution generates a classifier from the current multipliers.
+func (s *smoIterator) Solution(p *Problem) *CombinationClassifier {
+	var supportVectors []Sample
+	var coefficients []float64
+	for i, alpha := range s.alphas {
+		if alpha == 0 {
+			continue
+		}
+		if i < len(p.Positives) {
+			supportVectors = append(supportVectors, p.Positives[i])
+		} else {
+			supportVectors = append(supportVectors, p.Negatives[i-len(p.Positives)])
+		}
+		coefficients = append(coefficients, alpha*s.signs[i])
+	}
+	return &CombinationClassifier{
+		SupportVectors: supportVectors,
+		Coefficients:   coefficients,
+		Threshold:      -s.bias,
+		Kernel:         p.Kernel,
+	}
+}
+
+// dualityGap computes the difference between the primal and dual objectives for the current
+// multipliers and bias.
+func (s *smoIterator) dualityGap() float64 {
+	// With u_i = sum(alpha_j*y_j*K(x_j, x_i)), |w|^2 = sum(alpha_i*y_i*u_i) and the error
+	// margin of the i-th sample is max(0, 1-y_i*(u_i-bias)) = max(0, -y_i*E_i).
+	var normSquared, alphaSum, errorSum float64
+	for i, alpha := range s.alphas {
+		y := s.signs[i]
+		normSquared += alpha * y * (s.errors[i] + y + s.bias)
+		alphaSum += alpha
+		errorSum += math.Max(0, -y*s.errors[i])
+	}
+	return normSquared - alphaSum + s.maxCoeff*errorSum
+}
+
+// shrink removes the multipliers which are at a bound and satisfy the KKT conditions by more than
+// the tolerance from the active set.
+func (s *smoIterator) shrink() {
+	var active []int
+	for _, i := range s.active {
+		r := s.errors[i] * s.signs[i]
+		if !((s.alphas[i] == 0 && r > s.tolerance) ||
+			(s.alphas[i] == s.maxCoeff && r < -s.tolerance)) {
+			active = append(active, i)
+		}
+	}
+	s.active = active
+}
+
+// unshrink recomputes the errors of the inactive multipliers and makes every multiplier active.
+// It returns false if every multiplier was already active.
+func (s *smoIterator) unshrink() bool {
+	if len(s.active) == len(s.alphas) {
+		return false
+	}
+	isActive := make([]bool, len(s.alphas))
+	for _, i := range s.active {
+		isActive[i] = true
+	}
+	s.active = s.active[:0]
+	for i := range s.alphas {
+		s.active = append(s.active, i)
+		if isActive[i] {
+			continue
+		}
+		output := -s.bias
+		for j, alpha := range s.alphas {
+			if alpha != 0 {
+				output += alpha * s.signs[j] * s.kernel.At(j, i)
+			}
+		}
+		s.errors[i] = output - s.signs[i]
+	}
+	return true
+}
+
+func (s *smoIterator) atBound(i int) bool {
+	return s.alphas[i] == 0 || s.alphas[i] == s.maxCoeff
+}
+
+// examine checks whether the i-th multiplier violates the KKT conditions and, if it does, tries
+// to optimize it alongside a second multiplier.
+// It returns true if any progress was made.
+func (s *smoIterator) examine(i2 int) bool {
+	alpha2 := s.alphas[i2]
+	r2 := s.errors[i2] * s.signs[i2]
+	if !((r2 < -s.tolerance && alpha2 < s.maxCoeff) || (r2 > s.tolerance && alpha2 > 0)) {
+		return false
+	}
+
+	// Prefer the multiplier which maximizes the step size, approximated by |E1-E2|.
+	bestIdx := -1
+	var bestDiff float64
+	for _, i := range s.active {
+		if alpha := s.alphas[i]; alpha > 0 && alpha < s.maxCoeff {
+			diff := math.Abs(s.errors[i] - s.errors[i2])
+			if bestIdx < 0 || diff > bestDiff {
+				bestIdx = i
+				bestDiff = diff
+			}
+		}
+	}
+	if bestIdx >= 0 && s.takeStep(bestIdx, i2) {
+		return true
+	}
+
+	// Fall back on the non-bound multipliers, then on all of them, starting after i2.
+	start := sort.SearchInts(s.active, i2)
+	n := len(s.active)
+	for offset := 1; offset < n; offset++ {
+		i1 := s.active[(start+offset)%n]
+		if !s.atBound(i1) && s.takeStep(i1, i2) {
+			return true
+		}
+	}
+	for offset := 1; offset < n; offset++ {
+		i1 := s.active[(start+offset)%n]
+		if s.atBound(i1) && s.takeStep(i1, i2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// takeStep jointly optimizes two multipliers, returning false if they could not be improved.
+func (s *smoIterator) takeStep(i1, i2 int) bool {
+	if i1 == i2 {
+		return false
+	}
+
+	alpha1, alpha2 := s.alphas[i1], s.alphas[i2]
+	y1, y2 := s.signs[i1], s.signs[i2]
+	e1, e2 := s.errors[i1], s.errors[i2]
+	sign := y1 * y2
+
+	var low, high float64
+	if sign < 0 {
+		low = math.Max(0, alpha2-alpha1)
+		high = math.Min(s.maxCoeff, s.maxCoeff+alpha2-alpha1)
+	} else {
+		low = math.Max(0, alpha2+alpha1-s.maxCoeff)
+		high = math.Min(s.maxCoeff, alpha2+alpha1)
+	}
+	if low == high {
+		return false
+	}
+
+	k11, k12, k22 := s.kernel.At(i1, i1), s.kernel.At(i1, i2), s.kernel.At(i2, i2)
+	eta := k11 + k22 - 2*k12
+
+	var newAlpha2 float64
+	if eta > 0 {
+		newAlpha2 = alpha2 + y2*(e1-e2)/eta
+		newAlpha2 = math.Max(low, math.Min(high, newAlpha2))
+	} else {
+		// The objective is linear along the constraint line, so one of its ends is optimal.
+		f1 := y1*(e1+s.bias) - alpha1*k11 - sign*alpha2*k12
+		f2 := y2*(e2+s.bias) - sign*alpha1*k12 - alpha2*k22
+		lowAlpha1 := alpha1 + sign*(alpha2-low)
+		highAlpha1 := alpha1 + sign*(alpha2-high)
+		lowObj := lowAlpha1*f1 + low*f2 + lowAlpha1*lowAlpha1*k11/2 + low*low*k22/2 +
+			sign*low*lowAlpha1*k12
+		highObj := highAlpha1*f1 + high*f2 + highAlpha1*highAlpha1*k11/2 + high*high*k22/2 +
+			sign*high*highAlpha1*k12
+		if lowObj < highObj-smoStepEpsilon {
+			newAlpha2 = low
+		} else if lowObj > highObj+smoStepEpsilon {
+			newAlpha2 = high
+		} else {
+			newAlpha2 = alpha2
+		}
+	}
+
+	if math.Abs(newAlpha2-alpha2) < smoStepEpsilon*(newAlpha2+alpha2+smoStepEpsilon) {
+		return false
+	}
+
+	newAlpha1 := alpha1 + sign*(alpha2-newAlpha2)
+	if newAlpha1 < 0 {
+		newAlpha1 = 0
+	} else if newAlpha1 > s.maxCoeff {
+		newAlpha1 = s.maxCoeff
+	}
+
+	delta1 := y1 * (newAlpha1 - alpha1)
+	delta2 := y2 * (newAlpha2 - alpha2)
+
+	b1 := e1 + delta1*k11 + delta2*k12 + s.bias
+	b2 := e2 + delta1*k12 + delta2*k22 + s.bias
+	var newBias float64
+	if newAlpha1 > 0 && newAlpha1 < s.maxCoeff {
+		newBias = b1
+	} else if newAlpha2 > 0 && newAlpha2 < s.maxCoeff {
+		newBias = b2
+	} else {
+		newBias = (b1 + b2) / 2
+	}
+
+	for _, i := range s.active {
+		s.errors[i] += delta1*s.kernel.At(i1, i) + delta2*s.kernel.At(i2, i) + s.bias - newBias
+	}
+	s.bias = newBias
+	s.alphas[i1] = newAlpha1
+	s.alphas[i2] = newAlpha2
+
+	return true
+}
@@ -0,0 +1,154 @@
+package svm
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSMOSolverLinear(t *testing.T) {
+	problem, supportVec := linearSVMProblem(20)
+	solver := &SMOSolver{
+		Tradeoff:  0.0001,
+		Tolerance: 1e-6,
+	}
+	solution := solver.Solve(problem)
+
+	if math.Abs(solution.Threshold) > 1e-4 {
+		t.Error("unexpected threshold:", solution.Threshold)
+	}
+
+	normal := solution.Linearize().HyperplaneNormal.V
+	for i, x := range supportVec {
+		if math.Abs(x-normal[i]) > 1e-4 {
+			t.Fatal("unexpected normal:", normal)
+		}
+	}
+}
+
+func TestSMOSolverRBFKernel(t *testing.T) {
+	problem := ringProblem(20, 0.5, 2)
+	solver := &SMOSolver{Tradeoff: 0.0001}
+	solution := solver.Solve(problem)
+
+	for i, x := range problem.Positives {
+		if !solution.Classify(x) {
+			t.Error("misclassified positive", i)
+		}
+	}
+	for i, x := range problem.Negatives {
+		if solution.Classify(x) {
+			t.Error("misclassified negative", i)
+		}
+	}
+
+	if !solution.Classify(Sample{V: []float64{0.1, -0.2}}) {
+		t.Error("novel inner sample should be positive")
+	}
+	if solution.Classify(Sample{V: []float64{-1.5, 1.5}}) {
+		t.Error("novel outer sample should be negative")
+	}
+}
+
+// ringProblem generates a Problem whose positives lie on a circle of radius inner and whose
+// negatives lie on a circle of radius outer.
+// Such a problem can only be solved with a non-linear kernel.
+func ringProblem(count int, inner, outer float64) *Problem {
+	res := &Problem{Kernel: RadialBasisKernel(1)}
+	for i := 0; i < count; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(count)
+		x, y := math.Cos(angle), math.Sin(angle)
+		res.Positives = append(res.Positives, Sample{V: []float64{x * inner, y * inner}})
+		res.Negatives = append(res.Negatives, Sample{V: []float64{x * outer, y * outer}})
+	}
+	return res
+}
+
+func TestSMOSolverDualityGap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 30, 2, 0.1)
+	// SMO's bias is only updated for the pair of multipliers being optimized, so the gap may
+	// grow slightly from one pass to the next, but it shrinks steadily over longer spans.
+	// Shrinking is left off since the multipliers it sets aside may violate the KKT conditions
+	// for many passes before the solver examines them again.
+	lastGap := math.Inf(1)
+	for iterations := 1; iterations <= 64; iterations *= 2 {
+		solver := &SMOSolver{
+			Tradeoff:      0.01,
+			Tolerance:     1e-6,
+			MaxIterations: iterations,
+		}
+		_, gap := solver.SolveWithGap(problem)
+		if gap < -1e-9 {
+			t.Fatalf("%d iterations: negative gap %f", iterations, gap)
+		} else if gap > lastGap*(1+1e-3) {
+			t.Errorf("%d iterations: gap increased from %f to %f", iterations, lastGap, gap)
+		}
+		lastGap = gap
+	}
+	_, gap := (&SMOSolver{Tradeoff: 0.01, Tolerance: 1e-6}).SolveWithGap(problem)
+	if gap > 1e-5 {
+		t.Error("gap of converged solution is too large:", gap)
+	}
+
+	solver := &SMOSolver{Tradeoff: 0.01, Tolerance: 1e-6, GapTolerance: 0.1}
+	if _, gap := solver.SolveWithGap(problem); gap >= 0.1 {
+		t.Error("gap should be below GapTolerance but got", gap)
+	}
+}
+
+func TestSMOSolverShrinking(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, problem := range []*Problem{
+		noisyLinearProblem(rng, 100, 3, 0.1),
+		ringProblem(30, 0.5, 2),
+	} {
+		shrinking := NewSMOSolver(0.001)
+		shrinking.Tolerance = 1e-6
+		plain := &SMOSolver{Tradeoff: 0.001, Tolerance: 1e-6}
+		expected, expectedGap := plain.SolveWithGap(problem)
+		actual, actualGap := shrinking.SolveWithGap(problem)
+		if expectedGap > 1e-3 || actualGap > 1e-3 {
+			t.Errorf("solutions did not converge: gaps %f and %f", expectedGap, actualGap)
+		}
+		for _, samples := range [][]Sample{problem.Positives, problem.Negatives} {
+			for i, sample := range samples {
+				expectedRating, actualRating := expected.Rating(sample), actual.Rating(sample)
+				if math.Abs(expectedRating-actualRating) > 1e-3 {
+					t.Errorf("sample %d: rating should be %f but got %f", i, expectedRating,
+						actualRating)
+				}
+			}
+		}
+	}
+}
+
+func TestSMOSolverMaxRows(t *testing.T) {
+	problem := ringProblem(20, 0.5, 2)
+	expected := (&SMOSolver{Tradeoff: 0.0001}).Solve(problem)
+	solver := &SMOSolver{Tradeoff: 0.0001, MaxRows: 5}
+	actual, stats := solver.SolveWithCacheStats(problem)
+	if actual.Threshold != expected.Threshold ||
+		!reflect.DeepEqual(actual.Coefficients, expected.Coefficients) ||
+		!reflect.DeepEqual(actual.SupportVectors, expected.SupportVectors) {
+		t.Error("cached and uncached solutions differ")
+	}
+	if stats.Hits == 0 || stats.Misses < 5 {
+		t.Error("unexpected stats:", stats)
+	}
+}
+
+func BenchmarkSMOSolverShrinking(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 500, 10, 0.2)
+	for _, shrinking := range []bool{true, false} {
+		b.Run(fmt.Sprintf("Shrinking=%v", shrinking), func(b *testing.B) {
+			solver := &SMOSolver{Tradeoff: 0.001, Shrinking: shrinking}
+			for i := 0; i < b.N; i++ {
+				solver.Solve(problem)
+			}
+		})
+	}
+}
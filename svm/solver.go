@@ -0,0 +1,15 @@
+package svm
+
+// A Solver trains a LinearClassifier to classify the samples of a Problem.
+//
+// Meta-algorithms such as CrossValidate, GridSearch, TrainBagging, and TrainOVR accept any
+// Solver, so a new training method only needs a Solve method to work with all of them.
+type Solver interface {
+	Solve(p *Problem) *LinearClassifier
+}
+
+var (
+	_ Solver = (*SubgradientSolver)(nil)
+	_ Solver = (*PegasosSolver)(nil)
+	_ Solver = (*NuSubgradientSolver)(nil)
+)
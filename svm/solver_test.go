@@ -0,0 +1,117 @@
+package svm
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"testing"
+)
+
+// centroidSolver is a trivial Solver which separates the means of the two classes with the
+// hyperplane halfway between them.
+type centroidSolver struct {
+	calls int64
+}
+
+func (c *centroidSolver) Solve(p *Problem) *LinearClassifier {
+	atomic.AddInt64(&c.calls, 1)
+	mean := func(samples []Sample) []float64 {
+		res := make([]float64, len(samples[0].V))
+		for _, sample := range samples {
+			for i, x := range sample.V {
+				res[i] += x / float64(len(samples))
+			}
+		}
+		return res
+	}
+	positive, negative := mean(p.Positives), mean(p.Negatives)
+	normal := make([]float64, len(positive))
+	var threshold float64
+	for i, x := range positive {
+		normal[i] = x - negative[i]
+		threshold += normal[i] * (x + negative[i]) / 2
+	}
+	return &LinearClassifier{
+		HyperplaneNormal: Sample{V: normal},
+		Threshold:        threshold,
+		Kernel:           p.Kernel,
+	}
+}
+
+func TestSolverStub(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 40, 3, 0.5)
+	solver := &centroidSolver{}
+	if accuracy := problemAccuracy(solver.Solve(problem), problem); accuracy < 0.9 {
+		t.Fatal("stub solver is too inaccurate:", accuracy)
+	}
+
+	for i, score := range CrossValidate(problem, solver, 4, rng) {
+		if score < 0.8 {
+			t.Errorf("fold %d: unexpected accuracy %f", i, score)
+		}
+	}
+	if score := LeaveOneOutParallel(problem, solver, 4); score < 0.8 {
+		t.Error("unexpected leave-one-out accuracy:", score)
+	}
+	best, _ := GridSearch(problem, func(params map[string]float64) Solver {
+		return solver
+	}, ParameterGrid(map[string][]float64{"x": {1, 2}}), 3, rng)
+	if best.MeanScore < 0.8 {
+		t.Error("unexpected grid search accuracy:", best.MeanScore)
+	}
+	bagging := TrainBagging(problem, solver, 5, rng)
+	if accuracy := problemAccuracy(bagging, problem); accuracy < 0.9 {
+		t.Error("unexpected bagging accuracy:", accuracy)
+	}
+	if features, _ := RFE(problem, solver, 2); len(features) != 2 {
+		t.Error("unexpected RFE features:", features)
+	}
+	for _, point := range LearningCurve(problem, solver, []float64{0.5, 1}, rng) {
+		if point.ValidationAccuracy < 0.8 {
+			t.Errorf("fraction %f: unexpected accuracy %f", point.Fraction,
+				point.ValidationAccuracy)
+		}
+	}
+
+	multiclass := &MulticlassProblem{Kernel: LinearKernel}
+	for class, samples := range [][]Sample{problem.Positives, problem.Negatives} {
+		for _, sample := range samples {
+			multiclass.Samples = append(multiclass.Samples, sample)
+			multiclass.Classes = append(multiclass.Classes, class)
+		}
+	}
+	ovr, ovo := TrainOVR(multiclass, solver), TrainOVO(multiclass, solver)
+	for i, sample := range multiclass.Samples[:5] {
+		if ovr.Classify(sample) != 0 || ovo.Classify(sample) != 0 {
+			t.Errorf("sample %d: unexpected multiclass classification", i)
+		}
+	}
+
+	if atomic.LoadInt64(&solver.calls) == 0 {
+		t.Error("stub solver was never called")
+	}
+}
+
+func TestSolversRejectWeights(t *testing.T) {
+	problem := randomLinearProblem(rand.New(rand.NewSource(1)), 5, 2, 0)
+	problem.NegativeWeights = []float64{1, 2, 1, 2, 1}
+	solvers := map[string]func(){
+		"SMOSolver":     func() { (&SMOSolver{Tradeoff: 0.01}).Solve(problem) },
+		"PegasosSolver": func() { (&PegasosSolver{Lambda: 0.1, Iterations: 1}).Solve(problem) },
+		"KernelSubgradientSolver": func() {
+			(&KernelSubgradientSolver{Tradeoff: 0.01, Steps: 1}).Solve(problem)
+		},
+		"GradientDescentSolver": func() { (&GradientDescentSolver{Tradeoff: 0.01}).Solve(problem) },
+		"OneClassSolver":        func() { (&OneClassSolver{Nu: 0.5, Steps: 1}).Solve(problem) },
+	}
+	for name, solve := range solvers {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should reject sample weights", name)
+				}
+			}()
+			solve()
+		}()
+	}
+}
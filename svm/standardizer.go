@@ -0,0 +1,91 @@
+package svm
+
+import "math"
+
+// A Standardizer shifts and scales the components of Samples so that they have zero mean and unit
+// variance over the Problem it was fit to.
+//
+// Since the parameters are stored, the same transformation can be applied to new samples before
+// they are classified.
+type Standardizer struct {
+	Means   []float64
+	StdDevs []float64
+}
+
+// FitStandardizer computes the mean and standard deviation of every component of the samples in
+// a Problem.
+//
+// Components with zero variance are given a standard deviation of 1, so that they are centered
+// but not scaled.
+func FitStandardizer(p *Problem) *Standardizer {
+	count := p.sampleCount()
+	if count == 0 {
+		return &Standardizer{}
+	}
+	dim := len(p.Positives)
+	if dim > 0 {
+		dim = len(p.Positives[0].V)
+	} else {
+		dim = len(p.Negatives[0].V)
+	}
+
+	res := &Standardizer{
+		Means:   make([]float64, dim),
+		StdDevs: make([]float64, dim),
+	}
+	for i := 0; i < count; i++ {
+		sample, _ := p.sample(i)
+		for j, x := range sample.V {
+			res.Means[j] += x
+		}
+	}
+	for j := range res.Means {
+		res.Means[j] /= float64(count)
+	}
+
+	for i := 0; i < count; i++ {
+		sample, _ := p.sample(i)
+		for j, x := range sample.V {
+			diff := x - res.Means[j]
+			res.StdDevs[j] += diff * diff
+		}
+	}
+	for j, variance := range res.StdDevs {
+		if variance == 0 {
+			res.StdDevs[j] = 1
+		} else {
+			res.StdDevs[j] = math.Sqrt(variance / float64(count))
+		}
+	}
+
+	return res
+}
+
+// Transform returns a copy of a Problem with all of its samples standardized.
+// The Kernel and sample weights are preserved.
+func (s *Standardizer) Transform(p *Problem) *Problem {
+	res := &Problem{
+		Positives:       make([]Sample, len(p.Positives)),
+		Negatives:       make([]Sample, len(p.Negatives)),
+		Kernel:          p.Kernel,
+		PositiveWeights: p.PositiveWeights,
+		NegativeWeights: p.NegativeWeights,
+	}
+	for i, sample := range p.Positives {
+		res.Positives[i] = s.TransformSample(sample)
+	}
+	for i, sample := range p.Negatives {
+		res.Negatives[i] = s.TransformSample(sample)
+	}
+	return res
+}
+
+// TransformSample returns a standardized copy of a Sample.
+// The UserInfo of the sample is preserved.
+func (s *Standardizer) TransformSample(sample Sample) Sample {
+	res := Sample{V: make([]float64, len(sample.V)), UserInfo: sample.UserInfo}
+	for i, x := range sample.V {
+		res.V[i] = (x - s.Means[i]) / s.StdDevs[i]
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestStandardizerStatistics(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 100, 3, 0)
+	for _, samples := range [][]Sample{problem.Positives, problem.Negatives} {
+		for _, sample := range samples {
+			sample.V[0] = sample.V[0]*10 + 5
+			sample.V[1] = sample.V[1]*0.1 - 3
+		}
+	}
+
+	transformed := FitStandardizer(problem).Transform(problem)
+	if transformed.Kernel == nil {
+		t.Error("kernel was not preserved")
+	}
+	for dim := 0; dim < 3; dim++ {
+		var mean, variance float64
+		count := transformed.sampleCount()
+		for i := 0; i < count; i++ {
+			sample, _ := transformed.sample(i)
+			mean += sample.V[dim]
+		}
+		mean /= float64(count)
+		for i := 0; i < count; i++ {
+			sample, _ := transformed.sample(i)
+			variance += (sample.V[dim] - mean) * (sample.V[dim] - mean)
+		}
+		variance /= float64(count)
+		if math.Abs(mean) > 1e-10 {
+			t.Errorf("dimension %d: mean should be 0 but got %e", dim, mean)
+		}
+		if math.Abs(variance-1) > 1e-10 {
+			t.Errorf("dimension %d: variance should be 1 but got %f", dim, variance)
+		}
+	}
+}
+
+func TestStandardizerZeroVariance(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{1, 3}}, {V: []float64{3, 3}}},
+		Negatives: []Sample{{V: []float64{2, 3}}},
+		Kernel:    LinearKernel,
+	}
+	standardizer := FitStandardizer(problem)
+	actual := standardizer.TransformSample(Sample{V: []float64{2, 5}, UserInfo: 7})
+	if actual.V[0] != 0 || actual.V[1] != 2 {
+		t.Error("unexpected sample:", actual.V)
+	}
+	if actual.UserInfo != 7 {
+		t.Error("UserInfo was not preserved")
+	}
+}
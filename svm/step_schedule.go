@@ -0,0 +1,41 @@
+package svm
+
+import "math"
+
+// A StepSchedule computes the step size to use for a given descent step.
+// Steps are numbered starting at 0.
+//
+// Sub-gradient descent with a constant step size tends to bounce around the optimum rather than
+// settling on it.
+// A schedule which decays towards zero fixes this, and is guaranteed to converge when the
+// function being minimized is convex (as it is for linear kernels).
+// For non-linear kernels, there is no such guarantee.
+type StepSchedule func(step int) float64
+
+// scheduledStepSize returns the step size for a step of a solver which has a StepSchedule field
+// overriding a constant StepSize field.
+func scheduledStepSize(schedule StepSchedule, stepSize float64, step int) float64 {
+	if schedule != nil {
+		return schedule(step)
+	}
+	return stepSize
+}
+
+// InverseTimeDecay generates a StepSchedule which uses initial/(1+rate*step) for each step.
+func InverseTimeDecay(initial, rate float64) StepSchedule {
+	return func(step int) float64 {
+		return initial / (1 + rate*float64(step))
+	}
+}
+
+// StepDecay generates a StepSchedule which starts at initial and is multiplied by factor every
+// dropEvery steps.
+// It panics if dropEvery is not positive.
+func StepDecay(initial float64, dropEvery int, factor float64) StepSchedule {
+	if dropEvery <= 0 {
+		panic("step decay interval must be positive")
+	}
+	return func(step int) float64 {
+		return initial * math.Pow(factor, float64(step/dropEvery))
+	}
+}
@@ -0,0 +1,67 @@
+package svm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInverseTimeDecay(t *testing.T) {
+	schedule := InverseTimeDecay(0.1, 0.5)
+	expected := []float64{0.1, 0.1 / 1.5, 0.1 / 2, 0.1 / 2.5}
+	for step, x := range expected {
+		if actual := schedule(step); math.Abs(actual-x) > 1e-12 {
+			t.Errorf("step %d: expected %f but got %f", step, x, actual)
+		}
+	}
+}
+
+func TestStepDecay(t *testing.T) {
+	schedule := StepDecay(1, 3, 0.5)
+	expected := []float64{1, 1, 1, 0.5, 0.5, 0.5, 0.25}
+	for step, x := range expected {
+		if actual := schedule(step); actual != x {
+			t.Errorf("step %d: expected %f but got %f", step, x, actual)
+		}
+	}
+
+	for _, dropEvery := range []int{0, -3} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for dropEvery %d", dropEvery)
+				}
+			}()
+			StepDecay(1, dropEvery, 0.5)
+		}()
+	}
+}
+
+func TestSubgradientStepSchedule(t *testing.T) {
+	var steps []int
+	solver := &SubgradientSolver{
+		Tradeoff: 0.01,
+		Steps:    5,
+		StepSize: 1000,
+		StepSchedule: func(step int) float64 {
+			steps = append(steps, step)
+			return 0.001
+		},
+	}
+	problem := illConditionedProblem()
+	solution := solver.Solve(problem)
+
+	if len(steps) != solver.Steps {
+		t.Fatal("unexpected number of schedule calls:", len(steps))
+	}
+	for i, step := range steps {
+		if step != i {
+			t.Errorf("call %d had step %d", i, step)
+		}
+	}
+
+	expected := &SubgradientSolver{Tradeoff: 0.01, Steps: 5, StepSize: 0.001}
+	expectedSolution := expected.Solve(problem)
+	if solution.Threshold != expectedSolution.Threshold {
+		t.Error("schedule did not override StepSize")
+	}
+}
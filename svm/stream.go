@@ -0,0 +1,85 @@
+package svm
+
+import "fmt"
+
+// A LabeledSample is a Sample along with the class it belongs to.
+type LabeledSample struct {
+	Sample
+	Positive bool
+}
+
+// SolveStream trains a linear classifier online, taking one sub-gradient step for each sample
+// received from ch and returning once ch is closed.
+// This is useful when the samples do not fit in memory, or when they arrive over time.
+//
+// For the t-th sample (starting at 0), the step is the sub-gradient of that sample's weighted
+// error margin plus the regularization penalty, scaled by stepSize(t).
+// If stepSize is nil, s.StepSchedule or s.StepSize is used instead.
+// Since the samples are only seen once, a decaying schedule such as InverseTimeDecay usually works
+// best.
+//
+// The resulting classifier uses LinearKernel.
+// The Tradeoff, Regularization, RegularizeThreshold, loss, class weight, MaxGradientNorm, and Init
+// fields are honored; the other fields only apply to batch solves.
+// If no samples are received, the normal is empty unless s.Init is set.
+//
+// This panics if a sample has a different number of components than the first one (or than
+// s.Init).
+func (s *SubgradientSolver) SolveStream(ch <-chan LabeledSample,
+	stepSize StepSchedule) *LinearClassifier {
+	if stepSize == nil {
+		stepSize = s.stepSize
+	}
+	loss := s.loss()
+
+	var args softMarginArgs
+	initialized := false
+	if s.Init != nil {
+		args.normal = append([]float64{}, s.Init.HyperplaneNormal.V...)
+		args.threshold = s.Init.Threshold
+		initialized = true
+	}
+
+	var step int
+	for sample := range ch {
+		if !initialized {
+			args.normal = make([]float64, sample.Dim())
+			initialized = true
+		} else if sample.Dim() != len(args.normal) {
+			panic(fmt.Sprintf("sample %d has dimension %d (expected %d)", step, sample.Dim(),
+				len(args.normal)))
+		}
+
+		sign := -1.0
+		if sample.Positive {
+			sign = 1
+		}
+		margin := sign * (LinearKernel(Sample{V: args.normal}, sample.Sample) + args.threshold)
+		scale := sign * s.classWeight(sign) * loss.Gradient(margin)
+
+		grad := softMarginArgs{normal: make([]float64, len(args.normal)), threshold: scale}
+		for i, x := range args.normal {
+			grad.normal[i] = s.componentPenaltyGradient(x)
+		}
+		addScaled(grad.normal, sample.Sample, scale)
+		if s.RegularizeThreshold {
+			grad.threshold += s.componentPenaltyGradient(args.threshold)
+		}
+		if s.MaxGradientNorm != 0 {
+			grad = clipGradient(grad, s.MaxGradientNorm)
+		}
+
+		size := stepSize(step)
+		for i, x := range grad.normal {
+			args.normal[i] -= size * x
+		}
+		args.threshold -= size * grad.threshold
+		step++
+	}
+
+	return &LinearClassifier{
+		HyperplaneNormal: Sample{V: args.normal},
+		Threshold:        args.threshold,
+		Kernel:           LinearKernel,
+	}
+}
@@ -0,0 +1,44 @@
+package svm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSubgradientSolveStream(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 600, 3, 0.1)
+	train, test := problem.Split(0.25, rng)
+
+	ch := make(chan LabeledSample)
+	go func() {
+		defer close(ch)
+		for _, i := range rng.Perm(train.sampleCount()) {
+			sample, sign := train.sample(i)
+			ch <- LabeledSample{Sample: sample, Positive: sign > 0}
+		}
+	}()
+
+	solver := &SubgradientSolver{Tradeoff: 1e-4}
+	classifier := solver.SolveStream(ch, InverseTimeDecay(0.1, 0.01))
+	if len(classifier.HyperplaneNormal.V) != 3 {
+		t.Fatal("unexpected normal:", classifier.HyperplaneNormal.V)
+	}
+	if acc := problemAccuracy(classifier, test); acc < 0.97 {
+		t.Error("unexpected held-out accuracy:", acc)
+	}
+}
+
+func TestSubgradientSolveStreamDimension(t *testing.T) {
+	ch := make(chan LabeledSample, 2)
+	ch <- LabeledSample{Sample: NewSample(1, 2), Positive: true}
+	ch <- LabeledSample{Sample: NewSample(1, 2, 3)}
+	close(ch)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for mismatched dimensions")
+		}
+	}()
+	(&SubgradientSolver{StepSize: 0.1}).SolveStream(ch, nil)
+}
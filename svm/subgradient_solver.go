@@ -1,6 +1,41 @@
 package svm
 
-import "math"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+)
+
+const defaultDifferential = 1.0 / 10000.0
+
+// Regularization determines how a SubgradientSolver penalizes the magnitude of the normal vector.
+type Regularization int
+
+const (
+	// L2Regularization penalizes Tradeoff*Kernel(normal, normal), which is Tradeoff*|normal|^2
+	// for LinearKernel.
+	L2Regularization Regularization = iota
+
+	// L1Regularization penalizes Tradeoff*sum(|normal_i|).
+	// This tends to drive the weights of uninformative features to zero, producing sparse normals
+	// which can be used for feature selection.
+	// Since sub-gradient descent oscillates around the optimum, such weights usually end up very
+	// close to zero rather than exactly zero.
+	L1Regularization
+)
+
+// These constants control the backtracking line search used when LineSearch is set.
+const (
+	lineSearchMaxHalvings        = 30
+	lineSearchSufficientDecrease = 1e-4
+)
+
+// gradientChunkSize is the number of samples for which a single goroutine computes gradients.
+const gradientChunkSize = 128
 
 // A SubgradientSolver solves Problems using sub-gradient descent.
 //
@@ -15,84 +50,760 @@ type SubgradientSolver struct {
 
 	// Steps indicates how many descents the solver should make before returning its solution.
 	// Increasing the number of steps will increase the accuracy, but by decreasing amounts.
+	// If Tolerance is set, this is the maximum number of descents.
 	Steps int
 
+	// Tolerance, if non-zero, causes the solver to stop early once a descent changes the normal
+	// and threshold by less than Tolerance (measured as a Euclidean distance).
+	Tolerance float64
+
 	// StepSize is a number between 0 and 1 which determines how much of the gradient should be
 	// added to the current solution at each step.
 	// Values closer to 0 will result in better accuracy, while values closer to 1 will cause the
 	// solver to approach the solution in fewer steps.
 	StepSize float64
+
+	// StepSchedule, if non-nil, is used to compute the step size for each step, overriding
+	// StepSize.
+	StepSchedule StepSchedule
+
+	// LineSearch, if true, uses a backtracking line search to choose the size of each step.
+	// Starting from the step size given by StepSize or StepSchedule, the step size is halved until
+	// the step decreases the soft-margin function enough to satisfy the Armijo condition.
+	// If no such step can be found, the step is skipped.
+	// This makes the solver robust to a StepSize which is too large, at the cost of extra
+	// evaluations of the soft-margin function.
+	LineSearch bool
+
+	// Momentum is a number between 0 and 1 which determines how much of the previous step should
+	// be carried over into the next one.
+	// Momentum helps the solver avoid zig-zagging across narrow valleys in the soft-margin
+	// function.
+	// If this is zero, each step only depends on the current gradient.
+	Momentum float64
+
+	// Nesterov, if true, uses Nesterov's accelerated gradient rather than plain momentum.
+	// The gradient for each step is computed at the look-ahead point which the momentum alone
+	// would reach, normal+Momentum*velocity (and likewise for the threshold), rather than at the
+	// current solution.
+	// This corrects the momentum before it overshoots, which usually speeds up convergence.
+	// It has no effect unless Momentum is set.
+	Nesterov bool
+
+	// Optimizer, if non-nil, determines the direction of each step from the gradient, for
+	// example by adapting the step size of each component as Adagrad does.
+	// The step taken is the direction scaled by the step size, so StepSize, StepSchedule,
+	// LineSearch, and Momentum all still apply.
+	// If this is nil, each step is taken along the negative gradient.
+	Optimizer Optimizer
+
+	// MaxGradientNorm, if non-zero, is the largest Euclidean norm the gradient may have.
+	// Larger gradients are scaled down to this norm before each step, treating the threshold
+	// partial as an extra component.
+	// Clipping keeps badly scaled features or a large StepSize from pushing the solution to
+	// infinity.
+	MaxGradientNorm float64
+
+	// NumericGradient forces the solver to approximate the gradient using finite differences.
+	// By default, the gradient is computed analytically when the Problem uses LinearKernel and
+	// numerically for every other kernel, since the analytic form assumes a linear kernel.
+	NumericGradient bool
+
+	// Differential is the step used to approximate partial derivatives with finite differences.
+	// Badly scaled features may call for a different value.
+	// If this is zero, a default of 1e-4 is used.
+	Differential float64
+
+	// Regularization determines the penalty on the normal vector.
+	// The default is L2Regularization.
+	Regularization Regularization
+
+	// RegularizeThreshold, if true, applies the regularization penalty to the threshold as well as
+	// the normal, treating it as an extra component of the normal.
+	// By default, as in the standard soft-margin formulation, only the error margins depend on the
+	// threshold.
+	RegularizeThreshold bool
+
+	// Loss, if non-nil, determines the error margin of each sample from its margin, overriding
+	// Squared and SmoothWidth.
+	// If it is nil, HingeLoss is used unless Squared or SmoothWidth is set.
+	Loss Loss
+
+	// Squared, if true, squares the error margin of each sample (the "L2-SVM" or squared hinge
+	// loss), as SquaredHingeLoss does.
+	// This makes the soft-margin function differentiable everywhere, so the gradient is exact
+	// rather than a sub-gradient, and often leads to smoother convergence.
+	Squared bool
+
+	// SmoothWidth, if non-zero, replaces the kink in the error margin with a quadratic (as in the
+	// Huber loss), as SmoothHingeLoss does.
+	// The error margin for a sample with margin m becomes (1-m)^2/(2*SmoothWidth) for m in
+	// [1-SmoothWidth, 1], and 1-m-SmoothWidth/2 below that, so that its derivative is continuous.
+	// A small width keeps the loss close to the hinge loss while making the gradient less noisy
+	// near the margin.
+	// This has no effect if Squared is set, since the squared hinge loss is already smooth.
+	SmoothWidth float64
+
+	// Validation, if non-nil, enables early stopping.
+	// The accuracy on the Validation samples is checked every ValidationEvery steps, and the
+	// solver stops once Patience consecutive checks fail to improve upon the best accuracy so far.
+	// The solution with the best validation accuracy is returned.
+	//
+	// The Validation samples are classified using the Kernel of the Problem being solved, which
+	// should match the Kernel of the Validation problem.
+	Validation *Problem
+
+	// ValidationEvery is the number of steps between validation checks.
+	// If this is zero, the validation accuracy is checked after every step.
+	ValidationEvery int
+
+	// Patience is the number of consecutive validation checks without improvement after which the
+	// solver stops.
+	// If this is zero, the solver stops at the first check without improvement.
+	Patience int
+
+	// OnStep, if non-nil, is called after every step with the index of the step (starting at 0)
+	// and the value of the soft-margin function after the step.
+	// The soft-margin function is only evaluated for this purpose if OnStep is set.
+	OnStep func(step int, objective float64)
+
+	// Checkpoint, if non-nil, receives a checkpoint of the solver's progress every
+	// CheckpointEvery steps, which Resume can use to continue training after a restart.
+	// Each checkpoint is written as a separate JSON object, so Checkpoint may be an append-only
+	// log; Resume uses the last checkpoint it reads.
+	// If writing a checkpoint fails, the solver stops and SolveE, SolveContext, and Resume return
+	// the error, while Solve and SolveWithStatus panic.
+	Checkpoint io.Writer
+
+	// CheckpointEvery is the number of steps between checkpoints.
+	// If this is zero, a checkpoint is written after every step.
+	CheckpointEvery int
+
+	// PositiveWeight and NegativeWeight scale the error margins of the positive and negative
+	// samples, respectively.
+	// Giving the minority class a larger weight keeps the solver from favoring the majority class
+	// on imbalanced Problems; see InverseFrequencyWeights.
+	// If a weight is zero, a weight of 1 is used.
+	PositiveWeight float64
+	NegativeWeight float64
+
+	// Init, if non-nil, is the classifier from which the descent starts, rather than a zero
+	// normal and threshold.
+	// Starting from a previous solution, for example after adding a few samples to a Problem,
+	// can drastically reduce the number of steps needed.
+	// Its normal must have the same dimension as the samples of the Problem being solved.
+	// Only the normal and threshold are used; Init is not modified.
+	Init *LinearClassifier
+
+	// Workers is the number of goroutines used to compute gradients.
+	// If this is zero or negative, runtime.GOMAXPROCS(0) is used.
+	Workers int
 }
 
 func (s *SubgradientSolver) Solve(p *Problem) *LinearClassifier {
-	args := softMarginArgs{
-		normal: make([]float64, len(p.Positives[0].V)),
+	res, _ := s.SolveWithStatus(p)
+	return res
+}
+
+// SolveE is like Solve, but it returns an error rather than panicking if the Problem is invalid.
+// In particular, it returns ErrNoPositives or ErrNoNegatives for single-class Problems.
+// See Problem.Validate.
+// It also returns an error if s.Init does not match the dimension of the Problem.
+//
+// SolveE also checks that the normal and threshold remain finite after every step.
+// If they do not, it returns a *DivergedError for the step where they stopped being finite.
+// Since a Kernel which produces NaN or a soft-margin function which overflows makes the gradient
+// non-finite as well, this catches those problems too.
+func (s *SubgradientSolver) SolveE(p *Problem) (*LinearClassifier, error) {
+	state, err := s.initialState(p)
+	if err != nil {
+		return nil, err
 	}
+	res, _, err := s.solve(context.Background(), p, state)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
 
-	for i := 0; i < s.Steps; i++ {
-		args = s.descend(p, args)
+// SolveContext is like SolveE, but it stops early if ctx is done.
+//
+// The context is checked before every step.
+// Once it is done, SolveContext returns the best solution found so far along with ctx.Err().
+// The best solution is the one with the best validation accuracy if s.Validation is set, or the
+// latest one otherwise.
+func (s *SubgradientSolver) SolveContext(ctx context.Context, p *Problem) (*LinearClassifier,
+	error) {
+	state, err := s.initialState(p)
+	if err != nil {
+		return nil, err
 	}
+	res, _, err := s.solve(ctx, p, state)
+	if errors.Is(err, ErrDiverged) {
+		return nil, err
+	}
+	return res, err
+}
 
-	return &LinearClassifier{
-		HyperplaneNormal: Sample{V: args.normal},
-		Threshold:        args.threshold,
-		Kernel:           p.Kernel,
+// SolveWithStatus is like Solve, but it also reports whether the solver converged to within
+// s.Tolerance before running out of steps.
+//
+// This panics if the Problem is invalid, if s.Init does not match its dimension, or if a
+// checkpoint cannot be written.
+func (s *SubgradientSolver) SolveWithStatus(p *Problem) (*LinearClassifier, bool) {
+	state, err := s.initialState(p)
+	if err != nil {
+		panic("invalid problem: " + err.Error())
+	}
+	res, converged, err := s.solve(context.Background(), p, state)
+	if err != nil && !errors.Is(err, ErrDiverged) {
+		panic("failed to write checkpoint: " + err.Error())
 	}
+	return res, converged
 }
 
-func (s *SubgradientSolver) descend(p *Problem, args softMarginArgs) softMarginArgs {
-	res := args
-	res.normal = make([]float64, len(args.normal))
-	copy(res.normal, args.normal)
+// initialState validates a Problem and creates the state from which the descent starts, using
+// s.Init if it is set.
+func (s *SubgradientSolver) initialState(p *Problem) (*descentState, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	dim := p.Positives[0].Dim()
+	state := newDescentState(dim)
+	if s.Init != nil {
+		if len(s.Init.HyperplaneNormal.V) != dim {
+			return nil, fmt.Errorf("initial classifier has dimension %d (expected %d)",
+				len(s.Init.HyperplaneNormal.V), dim)
+		}
+		copy(state.args.normal, s.Init.HyperplaneNormal.V)
+		state.args.threshold = s.Init.Threshold
+	}
+	return state, nil
+}
+
+// solve runs the descent on a valid Problem, starting from the given state and continuing until
+// state.step reaches s.Steps.
+// If the solution stops being finite, it stops and returns the non-finite solution along with a
+// *DivergedError.
+// If ctx is done or a checkpoint cannot be written, it stops and returns the best solution so far
+// along with the error.
+func (s *SubgradientSolver) solve(ctx context.Context, p *Problem, state *descentState) (
+	*LinearClassifier, bool, error) {
+	stopper := s.newEarlyStopper(p, state.args)
+	best := func() *LinearClassifier {
+		if stopper != nil {
+			return stopper.best.classifier(p)
+		}
+		return state.args.classifier(p)
+	}
+
+	var converged bool
+	for state.step < s.Steps && !converged {
+		if err := ctx.Err(); err != nil {
+			return best(), false, err
+		}
+		i := state.step
+		oldArgs := state.args
+		s.descend(p, state)
+		if !state.args.finite() {
+			return state.args.classifier(p), false, &DivergedError{Step: i}
+		}
+		converged = s.Tolerance != 0 && state.args.distance(oldArgs) < s.Tolerance
+		if s.OnStep != nil {
+			s.OnStep(i, s.softMarginFunction(p, state.args))
+		}
+		if err := s.checkpoint(state); err != nil {
+			return best(), false, err
+		}
+		if stopper != nil && (i+1)%stopper.every == 0 && stopper.check(state.args) {
+			return stopper.best.classifier(p), false, nil
+		}
+	}
+
+	if stopper != nil {
+		stopper.check(state.args)
+		return stopper.best.classifier(p), converged, nil
+	}
+	return state.args.classifier(p), converged, nil
+}
+
+// SolveWithHistory is like Solve, but it also returns the value of the soft-margin function after
+// every step.
+// If s.OnStep is set, it is still called after every step.
+func (s *SubgradientSolver) SolveWithHistory(p *Problem) (*LinearClassifier, []float64) {
+	history := make([]float64, 0, s.Steps)
+	solver := *s
+	solver.OnStep = func(step int, objective float64) {
+		history = append(history, objective)
+		if s.OnStep != nil {
+			s.OnStep(step, objective)
+		}
+	}
+	return solver.Solve(p), history
+}
 
-	res.threshold -= s.thresholdPartial(p, args) * s.StepSize
-	for i := range res.normal {
-		res.normal[i] -= s.normalPartial(p, args, i) * s.StepSize
+// descend performs a single step of descent, replacing state.args with the new arguments.
+func (s *SubgradientSolver) descend(p *Problem, state *descentState) {
+	grad := s.gradient(p, s.gradientPoint(state))
+	if s.MaxGradientNorm != 0 {
+		grad = clipGradient(grad, s.MaxGradientNorm)
 	}
+	direction := grad
+	if s.Optimizer != nil {
+		if state.optimizer == nil {
+			state.optimizer = s.Optimizer.NewState(len(grad.normal) + 1)
+		}
+		flatDirection := state.optimizer.Direction(grad.flatten())
+		direction = softMarginArgs{
+			normal:    flatDirection[:len(grad.normal)],
+			threshold: flatDirection[len(grad.normal)],
+		}
+	}
+	stepSize := s.stepSize(state.step)
+
+	if s.LineSearch {
+		s.lineSearch(p, state, grad, direction, stepSize)
+	} else {
+		state.args, state.velocity = s.applyStep(state, direction, stepSize)
+	}
+	state.step++
+}
 
+// gradientPoint returns the arguments at which the gradient for the next step is computed, which
+// is the look-ahead point if s.Nesterov is set.
+func (s *SubgradientSolver) gradientPoint(state *descentState) softMarginArgs {
+	if !s.Nesterov || s.Momentum == 0 {
+		return state.args
+	}
+	res := softMarginArgs{
+		normal:    make([]float64, len(state.args.normal)),
+		threshold: state.args.threshold + s.Momentum*state.velocity.threshold,
+	}
+	for i, x := range state.args.normal {
+		res.normal[i] = x + s.Momentum*state.velocity.normal[i]
+	}
 	return res
 }
 
-// thresholdPartial approximates the partial differential of the soft-margin function with respect
-// to the threshold argument.
-func (s *SubgradientSolver) thresholdPartial(p *Problem, args softMarginArgs) float64 {
-	// TODO: figure out a good "differential" value.
-	differential := 1.0 / 10000.0
+// lineSearch halves the step size until a step along the given direction satisfies the Armijo
+// condition, then takes that step.
+// If no step size is acceptable, the arguments are left unchanged and the velocity is reset.
+func (s *SubgradientSolver) lineSearch(p *Problem, state *descentState, grad,
+	direction softMarginArgs, stepSize float64) {
+	objective := s.softMarginFunction(p, state.args)
+	slope := grad.threshold * direction.threshold
+	for i, x := range grad.normal {
+		slope += x * direction.normal[i]
+	}
 
-	tempArgs := args
-	tempArgs.threshold += differential
-	return (s.softMarginFunction(p, tempArgs) - s.softMarginFunction(p, args)) / differential
+	for i := 0; i < lineSearchMaxHalvings; i++ {
+		args, velocity := s.applyStep(state, direction, stepSize)
+		decrease := lineSearchSufficientDecrease * stepSize * slope
+		if s.softMarginFunction(p, args) <= objective-decrease {
+			state.args, state.velocity = args, velocity
+			return
+		}
+		stepSize /= 2
+	}
+	state.velocity = softMarginArgs{normal: make([]float64, len(grad.normal))}
 }
 
-// normalPartial approximates the partial differential of the soft-margin function with respect to
-// a component of the normal vector.
-func (s *SubgradientSolver) normalPartial(p *Problem, args softMarginArgs, comp int) float64 {
-	// TODO: figure out a good "differential" value.
-	differential := 1.0 / 10000.0
+// applyStep computes the arguments and velocity which result from taking a step along the
+// negative of the given direction from state.
+func (s *SubgradientSolver) applyStep(state *descentState, direction softMarginArgs,
+	stepSize float64) (args, velocity softMarginArgs) {
+	args = softMarginArgs{
+		normal:    make([]float64, len(direction.normal)),
+		threshold: state.args.threshold,
+	}
+	copy(args.normal, state.args.normal)
+	velocity = softMarginArgs{normal: make([]float64, len(direction.normal))}
 
-	tempArgs := args
-	tempArgs.normal = make([]float64, len(args.normal))
-	copy(tempArgs.normal, args.normal)
-	tempArgs.normal[comp] += differential
-	return (s.softMarginFunction(p, tempArgs) - s.softMarginFunction(p, args)) / differential
+	velocity.threshold = s.Momentum*state.velocity.threshold - direction.threshold*stepSize
+	args.threshold += velocity.threshold
+	for i, x := range direction.normal {
+		velocity.normal[i] = s.Momentum*state.velocity.normal[i] - x*stepSize
+		args.normal[i] += velocity.normal[i]
+	}
+	return
+}
+
+// clipGradient scales a gradient down so that its Euclidean norm is at most maxNorm.
+func clipGradient(grad softMarginArgs, maxNorm float64) softMarginArgs {
+	zero := softMarginArgs{normal: make([]float64, len(grad.normal))}
+	norm := grad.distance(zero)
+	if norm <= maxNorm {
+		return grad
+	}
+	scale := maxNorm / norm
+	res := softMarginArgs{
+		normal:    make([]float64, len(grad.normal)),
+		threshold: grad.threshold * scale,
+	}
+	for i, x := range grad.normal {
+		res.normal[i] = x * scale
+	}
+	return res
+}
+
+func (s *SubgradientSolver) stepSize(step int) float64 {
+	return scheduledStepSize(s.StepSchedule, s.StepSize, step)
+}
+
+// gradient computes the (sub-)gradient of the soft-margin function.
+func (s *SubgradientSolver) gradient(p *Problem, args softMarginArgs) softMarginArgs {
+	if s.NumericGradient || !isLinearKernel(p.Kernel) {
+		return s.numericGradient(p, args)
+	}
+	return s.analyticGradient(p, args)
+}
+
+// analyticGradient computes the sub-gradient of the soft-margin function in a single pass over
+// the samples, assuming that the kernel is LinearKernel.
+func (s *SubgradientSolver) analyticGradient(p *Problem, args softMarginArgs) softMarginArgs {
+	res := s.sumSampleGradients(p, args, s.analyticSampleGradient)
+	for i, x := range args.normal {
+		res.normal[i] += s.componentPenaltyGradient(x)
+	}
+	if s.RegularizeThreshold {
+		res.threshold += s.componentPenaltyGradient(args.threshold)
+	}
+	return res
+}
+
+// analyticSampleGradient computes the sub-gradient of the error margins for the samples in the
+// range [start, end).
+func (s *SubgradientSolver) analyticSampleGradient(p *Problem, args softMarginArgs,
+	start, end int) softMarginArgs {
+	loss := s.loss()
+	normalSample := Sample{V: args.normal}
+	res := softMarginArgs{normal: make([]float64, len(args.normal))}
+	for i := start; i < end; i++ {
+		sample, sign := p.sample(i)
+		margin := sign * (LinearKernel(normalSample, sample) + args.threshold)
+		if grad := loss.Gradient(margin); grad != 0 {
+			scale := sign * s.classWeight(sign) * p.sampleWeight(i) * grad
+			addScaled(res.normal, sample, scale)
+			res.threshold += scale
+		}
+	}
+	return res
+}
+
+// numericGradient approximates the gradient of the soft-margin function using central
+// differences, (f(x+h)-f(x-h))/(2h).
+//
+// Rather than re-evaluating the entire soft-margin function for every partial, this accumulates
+// the differences of each sample's error margin in a single pass over the samples.
+func (s *SubgradientSolver) numericGradient(p *Problem, args softMarginArgs) softMarginArgs {
+	res := s.sumSampleGradients(p, args, s.numericSampleGradient)
+	differential := s.differential()
+
+	shifted := Sample{V: make([]float64, len(args.normal))}
+	copy(shifted.V, args.normal)
+
+	for i, x := range args.normal {
+		shifted.V[i] = x + differential
+		forward := s.regularization(p, shifted)
+		shifted.V[i] = x - differential
+		backward := s.regularization(p, shifted)
+		shifted.V[i] = x
+		res.normal[i] += (forward - backward) / (2 * differential)
+	}
+
+	if s.RegularizeThreshold {
+		forward := s.thresholdRegularization(args.threshold + differential)
+		backward := s.thresholdRegularization(args.threshold - differential)
+		res.threshold += (forward - backward) / (2 * differential)
+	}
+
+	return res
+}
+
+// numericSampleGradient approximates the gradient of the error margins for the samples in the
+// range [start, end).
+func (s *SubgradientSolver) numericSampleGradient(p *Problem, args softMarginArgs,
+	start, end int) softMarginArgs {
+	res := softMarginArgs{normal: make([]float64, len(args.normal))}
+	loss := s.loss()
+	differential := s.differential()
+	normalSample := Sample{V: args.normal}
+	shifted := Sample{V: make([]float64, len(args.normal))}
+	copy(shifted.V, args.normal)
+
+	for i := start; i < end; i++ {
+		sample, sign := p.sample(i)
+		product := p.Kernel(normalSample, sample)
+		scale := s.classWeight(sign) * p.sampleWeight(i) / (2 * differential)
+
+		forward := loss.Value(sign * (product + args.threshold + differential))
+		backward := loss.Value(sign * (product + args.threshold - differential))
+		res.threshold += (forward - backward) * scale
+
+		for j, x := range args.normal {
+			shifted.V[j] = x + differential
+			forward := loss.Value(sign * (p.Kernel(shifted, sample) + args.threshold))
+			shifted.V[j] = x - differential
+			backward := loss.Value(sign * (p.Kernel(shifted, sample) + args.threshold))
+			shifted.V[j] = x
+			res.normal[j] += (forward - backward) * scale
+		}
+	}
+
+	return res
+}
+
+// sumSampleGradients splits the samples into fixed-size chunks, computes the gradient for each
+// chunk using up to s.Workers goroutines, and adds up the results.
+//
+// The chunks do not depend on the number of workers and are always added in the same order, so
+// the result does not depend on the number of workers either.
+func (s *SubgradientSolver) sumSampleGradients(p *Problem, args softMarginArgs,
+	f func(p *Problem, args softMarginArgs, start, end int) softMarginArgs) softMarginArgs {
+	sampleCount := p.sampleCount()
+	chunkCount := (sampleCount + gradientChunkSize - 1) / gradientChunkSize
+	partials := make([]softMarginArgs, chunkCount)
+
+	chunkGradient := func(chunk int) {
+		start := chunk * gradientChunkSize
+		end := start + gradientChunkSize
+		if end > sampleCount {
+			end = sampleCount
+		}
+		partials[chunk] = f(p, args, start, end)
+	}
+
+	if workers := s.workers(); workers == 1 || chunkCount < 2 {
+		for i := range partials {
+			chunkGradient(i)
+		}
+	} else {
+		chunks := make(chan int, chunkCount)
+		for i := range partials {
+			chunks <- i
+		}
+		close(chunks)
+
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for chunk := range chunks {
+					chunkGradient(chunk)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	res := softMarginArgs{normal: make([]float64, len(args.normal))}
+	for _, partial := range partials {
+		res.threshold += partial.threshold
+		for i, x := range partial.normal {
+			res.normal[i] += x
+		}
+	}
+	return res
+}
+
+func (s *SubgradientSolver) differential() float64 {
+	if s.Differential == 0 {
+		return defaultDifferential
+	}
+	return s.Differential
+}
+
+// classWeight returns the weight of samples with the given sign.
+func (s *SubgradientSolver) classWeight(sign float64) float64 {
+	weight := s.NegativeWeight
+	if sign > 0 {
+		weight = s.PositiveWeight
+	}
+	if weight == 0 {
+		return 1
+	}
+	return weight
+}
+
+func (s *SubgradientSolver) workers() int {
+	if s.Workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return s.Workers
 }
 
 func (s *SubgradientSolver) softMarginFunction(p *Problem, args softMarginArgs) float64 {
+	loss := s.loss()
 	normalSample := Sample{V: args.normal}
 
 	var matchSum float64
-	for _, positive := range p.Positives {
-		errorMargin := math.Max(0, 1-(p.Kernel(normalSample, positive)+args.threshold))
-		matchSum += errorMargin
+	for i := 0; i < p.sampleCount(); i++ {
+		sample, sign := p.sample(i)
+		value := loss.Value(sign * (p.Kernel(normalSample, sample) + args.threshold))
+		matchSum += s.classWeight(sign) * p.sampleWeight(i) * value
+	}
+	return matchSum + s.regularization(p, normalSample) + s.thresholdRegularization(args.threshold)
+}
+
+// regularization computes the penalty on the normal vector, including the Tradeoff factor.
+func (s *SubgradientSolver) regularization(p *Problem, normal Sample) float64 {
+	if s.Regularization == L1Regularization {
+		var sum float64
+		for _, x := range normal.V {
+			sum += math.Abs(x)
+		}
+		return s.Tradeoff * sum
 	}
-	for _, negative := range p.Negatives {
-		errorMargin := math.Max(0, 1+(p.Kernel(normalSample, negative)+args.threshold))
-		matchSum += errorMargin
+	return s.Tradeoff * p.Kernel(normal, normal)
+}
+
+// thresholdRegularization computes the penalty on the threshold, which is zero unless
+// s.RegularizeThreshold is set.
+func (s *SubgradientSolver) thresholdRegularization(threshold float64) float64 {
+	if !s.RegularizeThreshold {
+		return 0
+	} else if s.Regularization == L1Regularization {
+		return s.Tradeoff * math.Abs(threshold)
+	}
+	return s.Tradeoff * threshold * threshold
+}
+
+// componentPenaltyGradient computes the (sub-)derivative of the penalty on a single component,
+// assuming a linear kernel.
+func (s *SubgradientSolver) componentPenaltyGradient(x float64) float64 {
+	if s.Regularization != L1Regularization {
+		return 2 * s.Tradeoff * x
+	} else if x > 0 {
+		return s.Tradeoff
+	} else if x < 0 {
+		return -s.Tradeoff
+	}
+	return 0
+}
+
+// loss returns the Loss which determines the error margin of each sample.
+func (s *SubgradientSolver) loss() Loss {
+	if s.Loss != nil {
+		return s.Loss
+	} else if s.Squared {
+		return SquaredHingeLoss{}
+	} else if s.SmoothWidth != 0 {
+		return SmoothHingeLoss{Width: s.SmoothWidth}
+	}
+	return HingeLoss{}
+}
+
+// earlyStopper tracks the validation accuracy of a SubgradientSolver's solutions.
+type earlyStopper struct {
+	problem    *Problem
+	validation *Problem
+	every      int
+	patience   int
+
+	best         softMarginArgs
+	bestAccuracy float64
+	misses       int
+}
+
+// newEarlyStopper creates an earlyStopper if s.Validation is set, or returns nil otherwise.
+func (s *SubgradientSolver) newEarlyStopper(p *Problem, initial softMarginArgs) *earlyStopper {
+	if s.Validation == nil {
+		return nil
+	}
+	res := &earlyStopper{
+		problem:    p,
+		validation: s.Validation,
+		every:      s.ValidationEvery,
+		patience:   s.Patience,
+		best:       initial,
+	}
+	if res.every == 0 {
+		res.every = 1
+	}
+	if res.patience == 0 {
+		res.patience = 1
+	}
+	res.bestAccuracy = Accuracy(initial.classifier(p), s.Validation)
+	return res
+}
+
+// check records the validation accuracy of args and returns true if the solver should stop.
+func (e *earlyStopper) check(args softMarginArgs) bool {
+	accuracy := Accuracy(args.classifier(e.problem), e.validation)
+	if accuracy > e.bestAccuracy {
+		e.best = args
+		e.bestAccuracy = accuracy
+		e.misses = 0
+		return false
+	}
+	e.misses++
+	return e.misses >= e.patience
+}
+
+// InverseFrequencyWeights computes class weights which are inversely proportional to the number
+// of samples in each class, for use as a SubgradientSolver's PositiveWeight and NegativeWeight.
+//
+// The weights are normalized so that a balanced Problem gets a weight of 1 for both classes.
+func InverseFrequencyWeights(p *Problem) (positive, negative float64) {
+	total := float64(p.sampleCount())
+	positive = total / (2 * float64(len(p.Positives)))
+	negative = total / (2 * float64(len(p.Negatives)))
+	return
+}
+
+// descentState stores the parts of a descent which change from step to step.
+type descentState struct {
+	args      softMarginArgs
+	velocity  softMarginArgs
+	step      int
+	optimizer OptimizerState
+}
+
+func newDescentState(dimension int) *descentState {
+	return &descentState{
+		args:     softMarginArgs{normal: make([]float64, dimension)},
+		velocity: softMarginArgs{normal: make([]float64, dimension)},
 	}
-	return matchSum + s.Tradeoff*p.Kernel(normalSample, normalSample)
 }
 
 type softMarginArgs struct {
 	normal    []float64
 	threshold float64
 }
+
+func (s softMarginArgs) classifier(p *Problem) *LinearClassifier {
+	return &LinearClassifier{
+		HyperplaneNormal: Sample{V: s.normal},
+		Threshold:        s.threshold,
+		Kernel:           p.Kernel,
+	}
+}
+
+// flatten returns the normal followed by the threshold.
+func (s softMarginArgs) flatten() []float64 {
+	return append(append([]float64{}, s.normal...), s.threshold)
+}
+
+// finite returns true if the normal and threshold contain no NaNs or infinities.
+func (s softMarginArgs) finite() bool {
+	if math.IsNaN(s.threshold) || math.IsInf(s.threshold, 0) {
+		return false
+	}
+	for _, x := range s.normal {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// distance computes the Euclidean distance between two sets of arguments, treating the
+// threshold as an extra component.
+func (s softMarginArgs) distance(s1 softMarginArgs) float64 {
+	sum := math.Pow(s.threshold-s1.threshold, 2)
+	for i, x := range s.normal {
+		sum += math.Pow(x-s1.normal[i], 2)
+	}
+	return math.Sqrt(sum)
+}
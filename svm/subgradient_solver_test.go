@@ -0,0 +1,816 @@
+package svm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSubgradientAnalyticGradient(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 10; trial++ {
+		problem := randomLinearProblem(rng, 20, 4, 0)
+		solver := &SubgradientSolver{Tradeoff: 0.1}
+		args := randomSoftMarginArgs(rng, 4)
+
+		analytic := solver.analyticGradient(problem, args)
+		numeric := solver.numericGradient(problem, args)
+
+		if math.Abs(analytic.threshold-numeric.threshold) > 1e-3 {
+			t.Errorf("trial %d: threshold partial should be %f but got %f", trial,
+				numeric.threshold, analytic.threshold)
+		}
+		for i, x := range numeric.normal {
+			if math.Abs(analytic.normal[i]-x) > 1e-3 {
+				t.Errorf("trial %d: partial %d should be %f but got %f", trial, i, x,
+					analytic.normal[i])
+			}
+		}
+	}
+}
+
+func TestSubgradientNumericGradient(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 10, 0)
+	problem.Kernel = PolynomialKernel(1, 2)
+	solver := &SubgradientSolver{Tradeoff: 0.1}
+	args := randomSoftMarginArgs(rng, 10)
+
+	actual := solver.numericGradient(problem, args)
+	expected := solver.referenceNumericGradient(problem, args)
+
+	if math.Abs(actual.threshold-expected.threshold) > 1e-6 {
+		t.Errorf("threshold partial should be %f but got %f", expected.threshold,
+			actual.threshold)
+	}
+	for i, x := range expected.normal {
+		if math.Abs(actual.normal[i]-x) > 1e-6*math.Max(1, math.Abs(x)) {
+			t.Errorf("partial %d should be %f but got %f", i, x, actual.normal[i])
+		}
+	}
+}
+
+func TestSubgradientDifferential(t *testing.T) {
+	problem, args, expected := cubicGradientProblem()
+
+	var lastError float64
+	for i, differential := range []float64{1e-1, 1e-2, 1e-3} {
+		solver := &SubgradientSolver{Tradeoff: 0.5, Differential: differential}
+		grad := solver.numericGradient(problem, args)
+		var gradError float64
+		for j, x := range expected {
+			gradError += math.Abs(grad.normal[j] - solver.Tradeoff*x)
+		}
+		if i > 0 && gradError >= lastError {
+			t.Errorf("differential %f gave error %f, previous error was %f", differential,
+				gradError, lastError)
+		}
+		lastError = gradError
+	}
+}
+
+func TestSubgradientCentralDifferences(t *testing.T) {
+	problem, args, expected := cubicGradientProblem()
+	solver := &SubgradientSolver{Tradeoff: 0.5, Differential: 1e-2}
+	central := solver.numericGradient(problem, args)
+
+	var centralError, forwardError float64
+	base := solver.softMarginFunction(problem, args)
+	for i, x := range args.normal {
+		shifted := softMarginArgs{
+			normal:    append([]float64{}, args.normal...),
+			threshold: args.threshold,
+		}
+		shifted.normal[i] = x + solver.Differential
+		forward := (solver.softMarginFunction(problem, shifted) - base) / solver.Differential
+		forwardError += math.Abs(forward - solver.Tradeoff*expected[i])
+		centralError += math.Abs(central.normal[i] - solver.Tradeoff*expected[i])
+	}
+	if centralError >= forwardError/10 {
+		t.Errorf("central error %f should be far below forward error %f", centralError,
+			forwardError)
+	}
+}
+
+func TestSubgradientParallelDeterminism(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 500, 10, 0)
+	serial := &SubgradientSolver{
+		Tradeoff: 0.001,
+		Steps:    10,
+		StepSize: 0.001,
+		Workers:  1,
+	}
+	expected := serial.Solve(problem)
+	for _, workers := range []int{4, -1} {
+		parallel := *serial
+		parallel.Workers = workers
+		actual := parallel.Solve(problem)
+		if actual.Threshold != expected.Threshold {
+			t.Errorf("%d workers: threshold should be %v but got %v", workers, expected.Threshold,
+				actual.Threshold)
+		}
+		for i, x := range expected.HyperplaneNormal.V {
+			if actual.HyperplaneNormal.V[i] != x {
+				t.Errorf("%d workers: component %d should be %v but got %v", workers, i, x,
+					actual.HyperplaneNormal.V[i])
+			}
+		}
+	}
+}
+
+func TestSubgradientTolerance(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.2)
+	solver := &SubgradientSolver{
+		Tradeoff:  0.001,
+		Steps:     100000,
+		StepSize:  0.01,
+		Tolerance: 1e-4,
+	}
+
+	solution, converged := solver.SolveWithStatus(problem)
+	if !converged {
+		t.Fatal("expected convergence")
+	}
+
+	// Converging under a much smaller cap shows that the solver stopped well before solver.Steps.
+	capped := *solver
+	capped.Steps = solver.Steps / 100
+	if _, converged := capped.SolveWithStatus(problem); !converged {
+		t.Error("expected convergence within", capped.Steps, "steps")
+	}
+	if accuracy := problemAccuracy(solution, problem); accuracy != 1 {
+		t.Error("unexpected accuracy:", accuracy)
+	}
+}
+
+func TestSubgradientMomentum(t *testing.T) {
+	problem := illConditionedProblem()
+	plain := &SubgradientSolver{
+		Tradeoff: 0.01,
+		Steps:    100,
+		StepSize: 0.001,
+	}
+	momentum := *plain
+	momentum.Momentum = 0.9
+
+	plainObjective := plain.objective(problem, plain.Solve(problem))
+	momentumObjective := momentum.objective(problem, momentum.Solve(problem))
+	if momentumObjective >= plainObjective {
+		t.Errorf("momentum objective %f should be below plain objective %f",
+			momentumObjective, plainObjective)
+	}
+}
+
+func TestSubgradientNesterov(t *testing.T) {
+	problem := illConditionedProblem()
+	increases := map[bool]int{}
+	objectives := map[bool]float64{}
+	for _, nesterov := range []bool{false, true} {
+		lastObjective := math.Inf(1)
+		nesterov := nesterov
+		solver := &SubgradientSolver{
+			Tradeoff: 0.01,
+			Steps:    50,
+			StepSize: 0.004,
+			Momentum: 0.9,
+			Nesterov: nesterov,
+			OnStep: func(step int, objective float64) {
+				if objective > lastObjective {
+					increases[nesterov]++
+				}
+				lastObjective = objective
+				objectives[nesterov] = objective
+			},
+		}
+		solver.Solve(problem)
+	}
+
+	// With a large step size, plain momentum overshoots back and forth across the narrow valley,
+	// while the look-ahead gradient damps the oscillation.
+	if increases[true]*4 > increases[false] {
+		t.Errorf("Nesterov objective increased %d times but momentum objective only %d times",
+			increases[true], increases[false])
+	}
+	if objectives[true] >= objectives[false] {
+		t.Errorf("Nesterov objective %f should be below momentum objective %f",
+			objectives[true], objectives[false])
+	}
+}
+
+func TestSubgradientInit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 100, 3, 0.1)
+	solver := &SubgradientSolver{
+		Tradeoff:     0.01,
+		Steps:        2000,
+		StepSchedule: InverseTimeDecay(0.01, 0.01),
+	}
+	converged := solver.objective(problem, solver.Solve(problem))
+	solver.Steps = 1000
+	nearlyConverged := solver.Solve(problem)
+	initNormal := append([]float64{}, nearlyConverged.HyperplaneNormal.V...)
+
+	cold := &SubgradientSolver{Tradeoff: 0.01, Steps: 50, StepSize: 0.001}
+	warm := *cold
+	warm.Init = nearlyConverged
+	coldObjective := cold.objective(problem, cold.Solve(problem))
+	warmObjective := warm.objective(problem, warm.Solve(problem))
+	if math.Abs(warmObjective-converged) > 0.001*converged {
+		t.Errorf("warm objective %f should be close to %f", warmObjective, converged)
+	}
+	if coldObjective < 1.05*converged {
+		t.Errorf("cold objective %f should be far from %f", coldObjective, converged)
+	}
+	if !reflect.DeepEqual(nearlyConverged.HyperplaneNormal.V, initNormal) {
+		t.Error("Init was modified")
+	}
+
+	warm.Init = &LinearClassifier{HyperplaneNormal: NewSample(1, 2)}
+	if c, err := warm.SolveE(problem); err == nil || c != nil {
+		t.Error("expected error for mismatched Init")
+	}
+}
+
+func TestSubgradientMaxGradientNorm(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.1)
+	unclipped := &SubgradientSolver{Tradeoff: 1, Steps: 500, StepSize: 10}
+	if finiteClassifier(unclipped.Solve(problem)) {
+		t.Fatal("expected the unclipped solver to diverge")
+	}
+
+	clipped := *unclipped
+	clipped.MaxGradientNorm = 1
+	var maxObjective float64
+	clipped.OnStep = func(step int, objective float64) {
+		maxObjective = math.Max(maxObjective, objective)
+	}
+	if solution := clipped.Solve(problem); !finiteClassifier(solution) {
+		t.Error("clipped solution is not finite:", solution.HyperplaneNormal.V, solution.Threshold)
+	}
+	if maxObjective > 1e4 {
+		t.Error("objective grew too large:", maxObjective)
+	}
+}
+
+func TestSubgradientSolveEDiverged(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 1, Steps: 500, StepSize: 10}
+	c, err := solver.SolveE(problem)
+	if c != nil || !errors.Is(err, ErrDiverged) {
+		t.Fatal("expected divergence but got", err)
+	}
+	var diverged *DivergedError
+	if !errors.As(err, &diverged) || diverged.Step <= 0 || diverged.Step >= solver.Steps {
+		t.Error("unexpected divergence step:", err)
+	}
+
+	problem.Kernel = func(s1, s2 Sample) float64 {
+		return math.NaN()
+	}
+	solver = &SubgradientSolver{Tradeoff: 0.01, Steps: 10, StepSize: 0.01}
+	if _, err := solver.SolveE(problem); !errors.As(err, &diverged) || diverged.Step != 0 {
+		t.Error("expected divergence at the first step but got", err)
+	}
+
+	problem.Kernel = LinearKernel
+	if _, err := solver.SolveE(problem); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSubgradientClassWeights(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &Problem{Kernel: LinearKernel}
+	for i := 0; i < 200; i++ {
+		problem.Negatives = append(problem.Negatives,
+			Sample{V: []float64{rng.NormFloat64() - 1, rng.NormFloat64()}})
+	}
+	for i := 0; i < 10; i++ {
+		problem.Positives = append(problem.Positives,
+			Sample{V: []float64{rng.NormFloat64() + 1, rng.NormFloat64()}})
+	}
+
+	unweighted := &SubgradientSolver{Tradeoff: 0.01, Steps: 1000, StepSize: 0.001}
+	weighted := *unweighted
+	weighted.PositiveWeight, weighted.NegativeWeight = InverseFrequencyWeights(problem)
+	if weighted.PositiveWeight != 10.5 || weighted.NegativeWeight != 0.525 {
+		t.Errorf("unexpected weights: %f, %f", weighted.PositiveWeight, weighted.NegativeWeight)
+	}
+
+	positives := &Problem{Positives: problem.Positives, Kernel: problem.Kernel}
+	unweightedRecall := problemAccuracy(unweighted.Solve(problem), positives)
+	weightedRecall := problemAccuracy(weighted.Solve(problem), positives)
+	if weightedRecall <= unweightedRecall {
+		t.Errorf("weighted recall %f should exceed unweighted recall %f", weightedRecall,
+			unweightedRecall)
+	}
+}
+
+func TestSubgradientClassWeightGradient(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 4, 0)
+	solver := &SubgradientSolver{Tradeoff: 0.1, PositiveWeight: 3, NegativeWeight: 0.5}
+	args := randomSoftMarginArgs(rng, 4)
+
+	analytic := solver.analyticGradient(problem, args)
+	numeric := solver.referenceNumericGradient(problem, args)
+	if math.Abs(analytic.threshold-numeric.threshold) > 1e-3 {
+		t.Errorf("threshold partial should be %f but got %f", numeric.threshold,
+			analytic.threshold)
+	}
+	for i, x := range numeric.normal {
+		if math.Abs(analytic.normal[i]-x) > 1e-3 {
+			t.Errorf("partial %d should be %f but got %f", i, x, analytic.normal[i])
+		}
+	}
+}
+
+func TestSubgradientSquaredGradient(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 4, 0)
+	solver := &SubgradientSolver{Tradeoff: 0.1, Squared: true}
+	args := randomSoftMarginArgs(rng, 4)
+
+	analytic := solver.analyticGradient(problem, args)
+	numeric := solver.referenceNumericGradient(problem, args)
+	if math.Abs(analytic.threshold-numeric.threshold) > 1e-4 {
+		t.Errorf("threshold partial should be %f but got %f", numeric.threshold,
+			analytic.threshold)
+	}
+	for i, x := range numeric.normal {
+		if math.Abs(analytic.normal[i]-x) > 1e-4 {
+			t.Errorf("partial %d should be %f but got %f", i, x, analytic.normal[i])
+		}
+	}
+}
+
+func TestSubgradientSquared(t *testing.T) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{0.5}}, {V: []float64{2}}},
+		Negatives: []Sample{{V: []float64{-0.25}}},
+		Kernel:    LinearKernel,
+	}
+	args := softMarginArgs{normal: []float64{1}}
+	hinge := &SubgradientSolver{Tradeoff: 0.1}
+	squared := &SubgradientSolver{Tradeoff: 0.1, Squared: true}
+
+	// The margins are 0.5, 2, and 0.25.
+	hingeObjective := hinge.softMarginFunction(problem, args)
+	if expected := 0.5 + 0.75 + 0.1; math.Abs(hingeObjective-expected) > 1e-12 {
+		t.Errorf("hinge objective should be %f but got %f", expected, hingeObjective)
+	}
+	squaredObjective := squared.softMarginFunction(problem, args)
+	if expected := 0.25 + 0.5625 + 0.1; math.Abs(squaredObjective-expected) > 1e-12 {
+		t.Errorf("squared objective should be %f but got %f", expected, squaredObjective)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	separable := randomLinearProblem(rng, 50, 3, 0.2)
+	squared.Tradeoff = 0.001
+	squared.Steps = 500
+	squared.StepSize = 0.001
+	if accuracy := problemAccuracy(squared.Solve(separable), separable); accuracy != 1 {
+		t.Error("unexpected accuracy:", accuracy)
+	}
+}
+
+func TestSubgradientSmoothWidth(t *testing.T) {
+	solver := &SubgradientSolver{SmoothWidth: 0.5}
+	loss := solver.loss()
+	const eps = 1e-9
+	for _, transition := range []float64{0.5, 1} {
+		below, above := loss.Value(transition-eps), loss.Value(transition+eps)
+		if math.Abs(below-above) > 1e-8 {
+			t.Errorf("loss is discontinuous at %f: %f vs %f", transition, below, above)
+		}
+		below, above = loss.Gradient(transition-eps), loss.Gradient(transition+eps)
+		if math.Abs(below-above) > 1e-8 {
+			t.Errorf("slope is discontinuous at %f: %f vs %f", transition, below, above)
+		}
+	}
+	for _, margin := range []float64{-2, 0.3, 0.6, 0.9, 1.5} {
+		numeric := (loss.Value(margin+1e-6) - loss.Value(margin-1e-6)) / 2e-6
+		if actual := loss.Gradient(margin); math.Abs(actual-numeric) > 1e-6 {
+			t.Errorf("slope at %f should be %f but got %f", margin, numeric, actual)
+		}
+	}
+	if value := loss.Value(-1); value != 2-0.25 {
+		t.Error("unexpected linear loss:", value)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	separable := randomLinearProblem(rng, 50, 3, 0.2)
+	solver = &SubgradientSolver{Tradeoff: 0.001, Steps: 500, StepSize: 0.001, SmoothWidth: 0.1}
+	if accuracy := problemAccuracy(solver.Solve(separable), separable); accuracy != 1 {
+		t.Error("unexpected accuracy:", accuracy)
+	}
+	args := randomSoftMarginArgs(rng, 3)
+	analytic := solver.analyticGradient(separable, args)
+	numeric := solver.referenceNumericGradient(separable, args)
+	for i, x := range numeric.normal {
+		if math.Abs(analytic.normal[i]-x) > 1e-4 {
+			t.Errorf("partial %d should be %f but got %f", i, x, analytic.normal[i])
+		}
+	}
+}
+
+func TestSubgradientL1Regularization(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &Problem{Kernel: LinearKernel}
+	for len(problem.Positives) < 100 || len(problem.Negatives) < 100 {
+		sample := gaussianSample(rng, 10)
+		if sample.V[0]+sample.V[1] > 0.2 && len(problem.Positives) < 100 {
+			problem.Positives = append(problem.Positives, sample)
+		} else if sample.V[0]+sample.V[1] < -0.2 && len(problem.Negatives) < 100 {
+			problem.Negatives = append(problem.Negatives, sample)
+		}
+	}
+
+	l2 := &SubgradientSolver{
+		Tradeoff:     1,
+		Steps:        2000,
+		StepSchedule: InverseTimeDecay(0.01, 0.01),
+	}
+	l1 := *l2
+	l1.Regularization = L1Regularization
+
+	countZeros := func(c *LinearClassifier) int {
+		var count int
+		for _, x := range c.HyperplaneNormal.V {
+			if math.Abs(x) < 1e-2 {
+				count++
+			}
+		}
+		return count
+	}
+	l1Solution := l1.Solve(problem)
+	l1Zeros, l2Zeros := countZeros(l1Solution), countZeros(l2.Solve(problem))
+	if l1Zeros <= l2Zeros {
+		t.Errorf("L1 gave %d zero weights but L2 gave %d", l1Zeros, l2Zeros)
+	}
+	if accuracy := problemAccuracy(l1Solution, problem); accuracy < 0.95 {
+		t.Error("unexpected L1 accuracy:", accuracy)
+	}
+}
+
+func TestSubgradientL1Gradient(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 4, 0)
+	solver := &SubgradientSolver{Tradeoff: 0.1, Regularization: L1Regularization}
+	args := randomSoftMarginArgs(rng, 4)
+
+	analytic := solver.analyticGradient(problem, args)
+	numeric := solver.numericGradient(problem, args)
+	for i, x := range numeric.normal {
+		if math.Abs(analytic.normal[i]-x) > 1e-3 {
+			t.Errorf("partial %d should be %f but got %f", i, x, analytic.normal[i])
+		}
+	}
+}
+
+func TestSubgradientRegularizeThreshold(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 2, 0.2)
+	for _, samples := range [][]Sample{problem.Positives, problem.Negatives} {
+		for _, sample := range samples {
+			sample.V[0] += 3
+			sample.V[1] += 3
+		}
+	}
+
+	plain := &SubgradientSolver{
+		Tradeoff:     0.1,
+		Steps:        2000,
+		StepSchedule: InverseTimeDecay(0.01, 0.01),
+	}
+	regularized := *plain
+	regularized.RegularizeThreshold = true
+
+	plainThreshold := plain.Solve(problem).Threshold
+	regularizedThreshold := regularized.Solve(problem).Threshold
+	if math.Abs(regularizedThreshold) >= math.Abs(plainThreshold) {
+		t.Errorf("regularized threshold %f should be smaller than plain threshold %f",
+			regularizedThreshold, plainThreshold)
+	}
+
+	for _, regularization := range []Regularization{L1Regularization, L2Regularization} {
+		regularized.Regularization = regularization
+		args := randomSoftMarginArgs(rng, 2)
+		analytic := regularized.analyticGradient(problem, args)
+		numeric := regularized.numericGradient(problem, args)
+		if math.Abs(analytic.threshold-numeric.threshold) > 1e-3 {
+			t.Errorf("threshold partial should be %f but got %f", numeric.threshold,
+				analytic.threshold)
+		}
+	}
+}
+
+func TestSubgradientLineSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.1)
+	solver := &SubgradientSolver{Tradeoff: 0.01, StepSize: 100, LineSearch: true}
+
+	state := newDescentState(3)
+	lastObjective := solver.softMarginFunction(problem, state.args)
+	for i := 0; i < 100; i++ {
+		solver.descend(problem, state)
+		objective := solver.softMarginFunction(problem, state.args)
+		if objective > lastObjective {
+			t.Fatalf("step %d: objective increased from %f to %f", i, lastObjective, objective)
+		}
+		lastObjective = objective
+	}
+
+	unsearched := *solver
+	unsearched.LineSearch = false
+	unsearched.Steps = 100
+	unsearchedObjective := unsearched.objective(problem, unsearched.Solve(problem))
+	if lastObjective >= unsearchedObjective {
+		t.Errorf("line search objective %f should be below %f", lastObjective,
+			unsearchedObjective)
+	}
+}
+
+func TestSubgradientOnStep(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 3, 0.1)
+	var steps []int
+	var lastObjective float64
+	solver := &SubgradientSolver{
+		Tradeoff: 0.01,
+		Steps:    50,
+		StepSize: 0.001,
+		OnStep: func(step int, objective float64) {
+			steps = append(steps, step)
+			lastObjective = objective
+		},
+	}
+	solution := solver.Solve(problem)
+
+	if len(steps) != solver.Steps {
+		t.Fatalf("expected %d calls but got %d", solver.Steps, len(steps))
+	}
+	for i, step := range steps {
+		if step != i {
+			t.Fatalf("call %d had step %d", i, step)
+		}
+	}
+	if expected := solver.objective(problem, solution); lastObjective != expected {
+		t.Errorf("final objective should be %f but got %f", expected, lastObjective)
+	}
+}
+
+func TestSubgradientSolveContext(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var steps int
+	solver := &SubgradientSolver{
+		Tradeoff: 0.01,
+		Steps:    100000,
+		StepSize: 0.01,
+		OnStep: func(step int, objective float64) {
+			steps++
+			if step == 9 {
+				cancel()
+			}
+		},
+	}
+	solution, err := solver.SolveContext(ctx, problem)
+	if err != context.Canceled {
+		t.Fatal("expected context.Canceled but got", err)
+	}
+	if steps != 10 {
+		t.Errorf("expected 10 steps but got %d", steps)
+	}
+	if solution == nil || !finiteClassifier(solution) {
+		t.Fatal("expected a usable solution")
+	}
+	if accuracy := problemAccuracy(solution, problem); accuracy < 0.8 {
+		t.Error("bad accuracy for partial solution:", accuracy)
+	}
+
+	solver.OnStep = nil
+	solver.Steps = 100
+	if _, err := solver.SolveContext(context.Background(), problem); err != nil {
+		t.Error(err)
+	}
+	if _, err := solver.SolveContext(ctx, problem); err != context.Canceled {
+		t.Error("expected context.Canceled for a done context but got", err)
+	}
+}
+
+func TestSubgradientSolveWithHistory(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 50, 3, 0.2)
+	var callbacks int
+	solver := &SubgradientSolver{
+		Tradeoff:     0.001,
+		Steps:        500,
+		StepSchedule: InverseTimeDecay(0.01, 0.1),
+		OnStep: func(step int, objective float64) {
+			callbacks++
+		},
+	}
+	solution, history := solver.SolveWithHistory(problem)
+
+	if len(history) != solver.Steps {
+		t.Fatalf("expected %d entries but got %d", solver.Steps, len(history))
+	}
+	if callbacks != solver.Steps {
+		t.Errorf("expected %d callbacks but got %d", solver.Steps, callbacks)
+	}
+	if last := history[len(history)-1]; last != solver.objective(problem, solution) {
+		t.Error("unexpected final objective:", last)
+	}
+	for i := 50; i < len(history); i++ {
+		if history[i] > history[i-1] {
+			t.Fatalf("objective increased from %f to %f at step %d", history[i-1], history[i], i)
+		}
+	}
+}
+
+func TestSubgradientLinearKernelDetection(t *testing.T) {
+	if !isLinearKernel(LinearKernel) {
+		t.Error("LinearKernel not detected")
+	}
+	if isLinearKernel(PolynomialKernel(0, 1)) || isLinearKernel(CachedKernel(LinearKernel)) {
+		t.Error("wrapped kernel mistaken for LinearKernel")
+	}
+}
+
+func randomSoftMarginArgs(rng *rand.Rand, dim int) softMarginArgs {
+	res := softMarginArgs{
+		normal:    make([]float64, dim),
+		threshold: rng.NormFloat64(),
+	}
+	for i := range res.normal {
+		res.normal[i] = rng.NormFloat64()
+	}
+	return res
+}
+
+func BenchmarkSubgradientWorkers(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 5000, 100, 0)
+	args := randomSoftMarginArgs(rng, 100)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("Workers%d", workers), func(b *testing.B) {
+			solver := &SubgradientSolver{Tradeoff: 0.1, Workers: workers}
+			for i := 0; i < b.N; i++ {
+				solver.gradient(problem, args)
+			}
+		})
+	}
+}
+
+func BenchmarkSubgradientNumericGradient(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 500, 0)
+	solver := &SubgradientSolver{Tradeoff: 0.1}
+	args := randomSoftMarginArgs(rng, 500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		solver.numericGradient(problem, args)
+	}
+}
+
+func BenchmarkSubgradientReferenceGradient(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	problem := randomLinearProblem(rng, 20, 500, 0)
+	solver := &SubgradientSolver{Tradeoff: 0.1}
+	args := randomSoftMarginArgs(rng, 500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		solver.referenceNumericGradient(problem, args)
+	}
+}
+
+// referenceNumericGradient approximates the gradient with central differences, evaluating the
+// full soft-margin function twice for every partial.
+func (s *SubgradientSolver) referenceNumericGradient(p *Problem,
+	args softMarginArgs) softMarginArgs {
+	differential := s.differential()
+	shiftedArgs := func(comp int, amount float64) softMarginArgs {
+		res := softMarginArgs{
+			normal:    make([]float64, len(args.normal)),
+			threshold: args.threshold,
+		}
+		copy(res.normal, args.normal)
+		if comp < 0 {
+			res.threshold += amount
+		} else {
+			res.normal[comp] += amount
+		}
+		return res
+	}
+	partial := func(comp int) float64 {
+		forward := s.softMarginFunction(p, shiftedArgs(comp, differential))
+		backward := s.softMarginFunction(p, shiftedArgs(comp, -differential))
+		return (forward - backward) / (2 * differential)
+	}
+
+	res := softMarginArgs{
+		normal:    make([]float64, len(args.normal)),
+		threshold: partial(-1),
+	}
+	for i := range args.normal {
+		res.normal[i] = partial(i)
+	}
+	return res
+}
+
+// illConditionedProblem generates a separable Problem whose second feature has a much smaller
+// scale than its first.
+func illConditionedProblem() *Problem {
+	problem := randomLinearProblem(rand.New(rand.NewSource(1)), 50, 2, 0.1)
+	for _, samples := range [][]Sample{problem.Positives, problem.Negatives} {
+		for _, sample := range samples {
+			sample.V[1] *= 0.05
+		}
+	}
+	return problem
+}
+
+// cubicGradientProblem generates a Problem whose samples are all well outside the margin, so the
+// objective is Tradeoff*(w.w)^3.
+// It returns the gradient of (w.w)^3, which must be scaled by Tradeoff.
+func cubicGradientProblem() (*Problem, softMarginArgs, []float64) {
+	problem := &Problem{
+		Positives: []Sample{{V: []float64{1, 1}}},
+		Negatives: []Sample{{V: []float64{-1, -1}}},
+		Kernel:    PolynomialKernel(0, 3),
+	}
+	args := softMarginArgs{normal: []float64{1, 0.5}}
+	squaredNorm := args.normal[0]*args.normal[0] + args.normal[1]*args.normal[1]
+	gradient := make([]float64, len(args.normal))
+	for i, x := range args.normal {
+		gradient[i] = 6 * squaredNorm * squaredNorm * x
+	}
+	return problem, args, gradient
+}
+
+func (s *SubgradientSolver) objective(p *Problem, c *LinearClassifier) float64 {
+	return s.softMarginFunction(p, softMarginArgs{
+		normal:    c.HyperplaneNormal.V,
+		threshold: c.Threshold,
+	})
+}
+
+func TestSubgradientEarlyStopping(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	relevantProblem := func(count, dim int, noise float64) *Problem {
+		res := &Problem{Kernel: LinearKernel}
+		for i := 0; i < count; i++ {
+			for _, sign := range []float64{1, -1} {
+				sample := gaussianSample(rng, dim)
+				sample.V[0] += sign
+				if rng.Float64() < noise {
+					sign = -sign
+				}
+				if sign > 0 {
+					res.Positives = append(res.Positives, sample)
+				} else {
+					res.Negatives = append(res.Negatives, sample)
+				}
+			}
+		}
+		return res
+	}
+	training := relevantProblem(20, 50, 0.2)
+	validation := relevantProblem(200, 50, 0)
+	test := relevantProblem(200, 50, 0)
+
+	full := &SubgradientSolver{Tradeoff: 0.0001, Steps: 5000, StepSize: 0.001}
+	early := *full
+	early.Validation = validation
+	early.ValidationEvery = 10
+	early.Patience = 5
+
+	fullAccuracy := problemAccuracy(full.Solve(training), test)
+	earlyAccuracy := problemAccuracy(early.Solve(training), test)
+	if earlyAccuracy <= fullAccuracy {
+		t.Errorf("early stopping accuracy %f should beat full accuracy %f", earlyAccuracy,
+			fullAccuracy)
+	}
+}
+
+// finiteClassifier returns true if the normal and threshold of a classifier are finite.
+func finiteClassifier(c *LinearClassifier) bool {
+	for _, x := range append([]float64{c.Threshold}, c.HyperplaneNormal.V...) {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package svm
+
+import "math"
+
+// An SVRProblem defines everything needed to fit a support vector regression model, which
+// predicts real-valued targets for samples in a sample space.
+type SVRProblem struct {
+	Samples []Sample
+	Targets []float64
+	Kernel  Kernel
+}
+
+// A LinearRegressor predicts real values for samples using a hyperplane normal whose pre-image is
+// known.
+type LinearRegressor struct {
+	HyperplaneNormal Sample
+	Threshold        float64
+	Kernel           Kernel
+}
+
+// Predict returns Kernel(sample, HyperplaneNormal) plus the Threshold.
+func (r *LinearRegressor) Predict(sample Sample) float64 {
+	return r.Kernel(sample, r.HyperplaneNormal) + r.Threshold
+}
+
+// An SVRSolver solves SVRProblems using sub-gradient descent on the epsilon-insensitive loss.
+//
+// As with SubgradientSolver, this is only guaranteed to be effective for linear kernels.
+type SVRSolver struct {
+	// Tradeoff specifies how important it is to minimize the magnitude of the normal vector versus
+	// fitting the targets closely.
+	Tradeoff float64
+
+	// Epsilon is the width of the tube around the targets within which prediction errors are not
+	// penalized.
+	Epsilon float64
+
+	// Steps indicates how many descents the solver should make before returning its solution.
+	Steps int
+
+	// StepSize determines how much of the gradient should be added to the current solution at
+	// each step.
+	StepSize float64
+
+	// StepSchedule, if non-nil, is used to compute the step size for each step, overriding
+	// StepSize.
+	StepSchedule StepSchedule
+}
+
+func (s *SVRSolver) Solve(p *SVRProblem) *LinearRegressor {
+	args := softMarginArgs{normal: make([]float64, len(p.Samples[0].V))}
+	for i := 0; i < s.Steps; i++ {
+		grad := s.gradient(p, args)
+		stepSize := scheduledStepSize(s.StepSchedule, s.StepSize, i)
+		args.threshold -= grad.threshold * stepSize
+		for j, x := range grad.normal {
+			args.normal[j] -= x * stepSize
+		}
+	}
+	return &LinearRegressor{
+		HyperplaneNormal: Sample{V: args.normal},
+		Threshold:        args.threshold,
+		Kernel:           p.Kernel,
+	}
+}
+
+// gradient computes the (sub-)gradient of the SVR objective, analytically for LinearKernel and
+// with central differences for every other kernel.
+func (s *SVRSolver) gradient(p *SVRProblem, args softMarginArgs) softMarginArgs {
+	if isLinearKernel(p.Kernel) {
+		return s.analyticGradient(p, args)
+	}
+	return s.numericGradient(p, args)
+}
+
+func (s *SVRSolver) analyticGradient(p *SVRProblem, args softMarginArgs) softMarginArgs {
+	normalSample := Sample{V: args.normal}
+	res := softMarginArgs{normal: make([]float64, len(args.normal))}
+	for i, sample := range p.Samples {
+		residual := LinearKernel(normalSample, sample) + args.threshold - p.Targets[i]
+		if math.Abs(residual) > s.Epsilon {
+			sign := math.Copysign(1, residual)
+			for j, x := range sample.V {
+				res.normal[j] += sign * x
+			}
+			res.threshold += sign
+		}
+	}
+	for i, x := range args.normal {
+		res.normal[i] += 2 * s.Tradeoff * x
+	}
+	return res
+}
+
+func (s *SVRSolver) numericGradient(p *SVRProblem, args softMarginArgs) softMarginArgs {
+	res := softMarginArgs{normal: make([]float64, len(args.normal))}
+	normalSample := Sample{V: args.normal}
+	shifted := Sample{V: make([]float64, len(args.normal))}
+	copy(shifted.V, args.normal)
+	scale := 1 / (2 * defaultDifferential)
+
+	for i, sample := range p.Samples {
+		target := p.Targets[i]
+		product := p.Kernel(normalSample, sample)
+		forward := s.sampleLoss(product + args.threshold + defaultDifferential - target)
+		backward := s.sampleLoss(product + args.threshold - defaultDifferential - target)
+		res.threshold += (forward - backward) * scale
+
+		for j, x := range args.normal {
+			shifted.V[j] = x + defaultDifferential
+			forward := s.sampleLoss(p.Kernel(shifted, sample) + args.threshold - target)
+			shifted.V[j] = x - defaultDifferential
+			backward := s.sampleLoss(p.Kernel(shifted, sample) + args.threshold - target)
+			shifted.V[j] = x
+			res.normal[j] += (forward - backward) * scale
+		}
+	}
+
+	for i, x := range args.normal {
+		shifted.V[i] = x + defaultDifferential
+		forward := p.Kernel(shifted, shifted)
+		shifted.V[i] = x - defaultDifferential
+		backward := p.Kernel(shifted, shifted)
+		shifted.V[i] = x
+		res.normal[i] += s.Tradeoff * (forward - backward) * scale
+	}
+
+	return res
+}
+
+// sampleLoss computes the epsilon-insensitive loss of a prediction error.
+func (s *SVRSolver) sampleLoss(residual float64) float64 {
+	return math.Max(0, math.Abs(residual)-s.Epsilon)
+}
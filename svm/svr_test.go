@@ -0,0 +1,57 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSVRSolverSlope(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &SVRProblem{Kernel: LinearKernel}
+	for i := 0; i < 200; i++ {
+		x := rng.Float64()*4 - 2
+		problem.Samples = append(problem.Samples, Sample{V: []float64{x}})
+		problem.Targets = append(problem.Targets, 3*x+1+rng.NormFloat64()*0.1)
+	}
+
+	solver := &SVRSolver{
+		Tradeoff:     0.01,
+		Epsilon:      0.05,
+		Steps:        2000,
+		StepSchedule: InverseTimeDecay(0.01, 0.01),
+	}
+	regressor := solver.Solve(problem)
+	if slope := regressor.HyperplaneNormal.V[0]; math.Abs(slope-3) > 0.1 {
+		t.Error("expected slope near 3 but got", slope)
+	}
+	if intercept := regressor.Threshold; math.Abs(intercept-1) > 0.1 {
+		t.Error("expected intercept near 1 but got", intercept)
+	}
+	if prediction := regressor.Predict(Sample{V: []float64{1}}); math.Abs(prediction-4) > 0.2 {
+		t.Error("expected prediction near 4 but got", prediction)
+	}
+}
+
+func TestSVRSolverGradient(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := &SVRProblem{Kernel: PolynomialKernel(0, 1)}
+	for i := 0; i < 20; i++ {
+		problem.Samples = append(problem.Samples, gaussianSample(rng, 3))
+		problem.Targets = append(problem.Targets, rng.NormFloat64())
+	}
+	solver := &SVRSolver{Tradeoff: 0.1, Epsilon: 0.1}
+	args := randomSoftMarginArgs(rng, 3)
+
+	analytic := solver.analyticGradient(problem, args)
+	numeric := solver.numericGradient(problem, args)
+	if math.Abs(analytic.threshold-numeric.threshold) > 1e-3 {
+		t.Errorf("threshold partial should be %f but got %f", numeric.threshold,
+			analytic.threshold)
+	}
+	for i, x := range numeric.normal {
+		if math.Abs(analytic.normal[i]-x) > 1e-3 {
+			t.Errorf("partial %d should be %f but got %f", i, x, analytic.normal[i])
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package svm
+
+import (
+	"math"
+	"sort"
+)
+
+// OptimalThreshold finds the decision threshold which minimizes the total cost of a
+// LinearClassifier's mistakes on a Problem, where each false positive costs fpCost and each false
+// negative costs fnCost.
+// If the Problem has per-sample weights, the cost of each mistake is multiplied by the weight of
+// the sample.
+//
+// A sample is classified as positive if its DecisionValue is greater than the threshold, so a
+// threshold of 0 corresponds to Classify.
+// Use WithThreshold to create a classifier which applies the threshold.
+//
+// The candidate thresholds are the midpoints between consecutive distinct decision values of
+// the samples, plus a threshold below all of them and one at the largest of them.
+// If several thresholds have the same cost, the one closest to 0 is used.
+// If the Problem has no samples, NaN is returned.
+func OptimalThreshold(c *LinearClassifier, p *Problem, fpCost, fnCost float64) float64 {
+	if p.sampleCount() == 0 {
+		return math.NaN()
+	}
+
+	type scoredSample struct {
+		value  float64
+		sign   float64
+		weight float64
+	}
+	scores := make([]scoredSample, p.sampleCount())
+	for i := range scores {
+		sample, sign := p.sample(i)
+		scores[i] = scoredSample{c.DecisionValue(sample), sign, p.sampleWeight(i)}
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].value < scores[j].value
+	})
+
+	// Start with every sample classified as positive.
+	var cost float64
+	for _, s := range scores {
+		if s.sign < 0 {
+			cost += fpCost * s.weight
+		}
+	}
+	bestThreshold := math.Nextafter(scores[0].value, math.Inf(-1))
+	bestCost := cost
+
+	for i := 0; i < len(scores); {
+		value := scores[i].value
+		for ; i < len(scores) && scores[i].value == value; i++ {
+			if scores[i].sign > 0 {
+				cost += fnCost * scores[i].weight
+			} else {
+				cost -= fpCost * scores[i].weight
+			}
+		}
+		threshold := value
+		if i < len(scores) {
+			threshold = (value + scores[i].value) / 2
+		}
+		if cost < bestCost || (cost == bestCost && math.Abs(threshold) < math.Abs(bestThreshold)) {
+			bestThreshold = threshold
+			bestCost = cost
+		}
+	}
+
+	return bestThreshold
+}
+
+// WithThreshold creates a copy of the classifier which classifies a sample as positive if its
+// original DecisionValue is greater than threshold, as returned by OptimalThreshold.
+// The DecisionValue of every sample is reduced by threshold.
+// The copy shares the HyperplaneNormal of c.
+func (c *LinearClassifier) WithThreshold(threshold float64) *LinearClassifier {
+	res := *c
+	res.Threshold -= threshold
+	return &res
+}
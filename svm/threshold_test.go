@@ -0,0 +1,62 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOptimalThreshold(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	problem := noisyLinearProblem(rng, 100, 2, 0.1)
+	classifier := (&SubgradientSolver{Tradeoff: 0.01, Steps: 100, StepSize: 0.001}).Solve(problem)
+
+	mistakeCost := func(c Classifier, fpCost, fnCost float64) float64 {
+		var cost float64
+		for _, x := range problem.Positives {
+			if !c.Classify(x) {
+				cost += fnCost
+			}
+		}
+		for _, x := range problem.Negatives {
+			if c.Classify(x) {
+				cost += fpCost
+			}
+		}
+		return cost
+	}
+
+	thresholds := map[[2]float64]float64{}
+	for _, costs := range [][2]float64{{1, 1}, {10, 1}, {1, 10}} {
+		threshold := OptimalThreshold(classifier, problem, costs[0], costs[1])
+		thresholds[costs] = threshold
+		shifted := classifier.WithThreshold(threshold)
+		cost := mistakeCost(shifted, costs[0], costs[1])
+		for i := 0; i < problem.sampleCount(); i++ {
+			sample, _ := problem.sample(i)
+			other := classifier.WithThreshold(classifier.DecisionValue(sample))
+			if otherCost := mistakeCost(other, costs[0], costs[1]); otherCost < cost {
+				t.Errorf("costs %v: threshold %f costs %f but %f costs %f", costs, threshold,
+					cost, classifier.DecisionValue(sample), otherCost)
+				break
+			}
+		}
+	}
+
+	symmetric := thresholds[[2]float64{1, 1}]
+	if thresholds[[2]float64{10, 1}] <= symmetric {
+		t.Errorf("costly false positives should raise the threshold: %f (symmetric %f)",
+			thresholds[[2]float64{10, 1}], symmetric)
+	}
+	if thresholds[[2]float64{1, 10}] >= symmetric {
+		t.Errorf("costly false negatives should lower the threshold: %f (symmetric %f)",
+			thresholds[[2]float64{1, 10}], symmetric)
+	}
+
+	if classifier.WithThreshold(0).Threshold != classifier.Threshold {
+		t.Error("a zero threshold should not change the classifier")
+	}
+	if !math.IsNaN(OptimalThreshold(classifier, &Problem{Kernel: LinearKernel}, 1, 1)) {
+		t.Error("expected NaN for an empty problem")
+	}
+}
@@ -0,0 +1,268 @@
+package svm
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A Vector is a list of components which may be stored densely (as in a Sample) or sparsely (as
+// in a SparseSample).
+//
+// A SparseSample can be trained on and classified by wrapping it in a Sample with its Sample
+// method; see Sample.Sparse for which parts of the package support such Samples.
+type Vector interface {
+	// Dim returns the number of components in the vector, including zero components.
+	Dim() int
+
+	// At returns the i-th component of the vector.
+	At(i int) float64
+
+	// ForEachNonZero calls f for every component which may be non-zero, in ascending order.
+	ForEachNonZero(f func(i int, x float64))
+}
+
+// A VectorKernel is like a Kernel, but it works on any kind of Vector.
+type VectorKernel func(v1, v2 Vector) float64
+
+// Kernel converts a VectorKernel into a Kernel which works on Samples.
+// Samples which store their components sparsely are passed to v as such.
+func (v VectorKernel) Kernel() Kernel {
+	return func(s1, s2 Sample) float64 {
+		return v(s1, s2)
+	}
+}
+
+// LinearVectorKernel is the VectorKernel equivalent of LinearKernel.
+// Products involving SparseSamples only visit their non-zero components.
+func LinearVectorKernel(v1, v2 Vector) float64 {
+	if v1.Dim() != v2.Dim() {
+		panic("samples must be of the sample dimension")
+	}
+	return dot(v1, v2)
+}
+
+// RadialBasisVectorKernel is the VectorKernel equivalent of RadialBasisKernel.
+// Products involving SparseSamples only visit their non-zero components.
+func RadialBasisVectorKernel(coeff float64) VectorKernel {
+	return func(v1, v2 Vector) float64 {
+		if v1.Dim() != v2.Dim() {
+			panic("samples must be of the sample dimension")
+		}
+		return math.Exp(-coeff * squaredDistance(v1, v2))
+	}
+}
+
+func (s Sample) Dim() int {
+	if s.Sparse != nil {
+		return s.Sparse.Dim()
+	}
+	return len(s.V)
+}
+
+func (s Sample) At(i int) float64 {
+	if s.Sparse != nil {
+		return s.Sparse.At(i)
+	}
+	return s.V[i]
+}
+
+func (s Sample) ForEachNonZero(f func(i int, x float64)) {
+	if s.Sparse != nil {
+		s.Sparse.ForEachNonZero(f)
+		return
+	}
+	for i, x := range s.V {
+		f(i, x)
+	}
+}
+
+// A SparseSample is a Sample which only stores its non-zero components.
+//
+// Indices lists the indices of the stored components in ascending order, and Values lists the
+// corresponding values.
+// Length is the total number of components, including the ones which are not stored.
+//
+// A SparseSample can be passed to a VectorKernel directly, or to a Kernel after wrapping it with
+// its Sample method.
+type SparseSample struct {
+	Indices []int
+	Values  []float64
+	Length  int
+
+	// UserInfo serves the same purpose as Sample.UserInfo.
+	UserInfo int
+}
+
+// NewSparseSample creates a SparseSample from a map of indices to values, such as the word
+// counts of a bag-of-words model.
+//
+// Zero values are not stored.
+// The Length of the result is one more than its largest index, since the map does not say how
+// many components there are; use Dense to convert to a Sample of a specific dimension.
+// This panics if any index is negative.
+func NewSparseSample(m map[int]float64) SparseSample {
+	var res SparseSample
+	for i, x := range m {
+		if i < 0 {
+			panic("sparse sample indices must be non-negative")
+		}
+		if x != 0 {
+			res.Indices = append(res.Indices, i)
+		}
+	}
+	sort.Ints(res.Indices)
+	for _, i := range res.Indices {
+		res.Values = append(res.Values, m[i])
+	}
+	if len(res.Indices) > 0 {
+		res.Length = res.Indices[len(res.Indices)-1] + 1
+	}
+	return res
+}
+
+// Dense converts the sample into a Sample with dim components, filling in the components which
+// are not stored with zeros.
+// It returns an error if any stored component has an index of dim or more.
+// The UserInfo of the sample is carried over.
+func (s SparseSample) Dense(dim int) (Sample, error) {
+	res := Sample{V: make([]float64, dim), UserInfo: s.UserInfo}
+	for j, i := range s.Indices {
+		if i >= dim {
+			return Sample{}, fmt.Errorf("index %d out of bounds for dimension %d", i, dim)
+		}
+		res.V[i] = s.Values[j]
+	}
+	return res, nil
+}
+
+// Sample wraps the sample in a Sample which stores its components sparsely, so that it can be
+// added to a Problem or classified without converting it with Dense.
+// The Sample shares the stored components of s, and the UserInfo of s is carried over.
+func (s SparseSample) Sample() Sample {
+	return Sample{Sparse: &s, UserInfo: s.UserInfo}
+}
+
+func (s SparseSample) Dim() int {
+	return s.Length
+}
+
+// At returns the i-th component of the sample, performing a binary search over s.Indices.
+func (s SparseSample) At(i int) float64 {
+	low, high := 0, len(s.Indices)
+	for low < high {
+		mid := (low + high) / 2
+		if s.Indices[mid] < i {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	if low < len(s.Indices) && s.Indices[low] == i {
+		return s.Values[low]
+	}
+	return 0
+}
+
+func (s SparseSample) ForEachNonZero(f func(i int, x float64)) {
+	for j, i := range s.Indices {
+		f(i, s.Values[j])
+	}
+}
+
+// sparseVector returns the sparse form of a Vector, or false if it is stored densely.
+func sparseVector(v Vector) (SparseSample, bool) {
+	switch v := v.(type) {
+	case SparseSample:
+		return v, true
+	case *SparseSample:
+		return *v, true
+	case Sample:
+		if v.Sparse != nil {
+			return *v.Sparse, true
+		}
+	}
+	return SparseSample{}, false
+}
+
+// addScaled adds scale times each component of s to dst, only visiting the stored components of
+// sparse samples.
+func addScaled(dst []float64, s Sample, scale float64) {
+	if s.Sparse == nil {
+		for i, x := range s.V {
+			dst[i] += scale * x
+		}
+		return
+	}
+	for j, i := range s.Sparse.Indices {
+		dst[i] += scale * s.Sparse.Values[j]
+	}
+}
+
+func dot(v1, v2 Vector) float64 {
+	sparse1, ok1 := sparseVector(v1)
+	sparse2, ok2 := sparseVector(v2)
+	if ok1 && ok2 {
+		var sum float64
+		var j int
+		for i, idx := range sparse1.Indices {
+			for j < len(sparse2.Indices) && sparse2.Indices[j] < idx {
+				j++
+			}
+			if j < len(sparse2.Indices) && sparse2.Indices[j] == idx {
+				sum += sparse1.Values[i] * sparse2.Values[j]
+			}
+		}
+		return sum
+	} else if !ok1 && !ok2 {
+		if s1, ok := v1.(Sample); ok {
+			if s2, ok := v2.(Sample); ok {
+				return LinearKernel(s1, s2)
+			}
+		}
+	}
+
+	// When one vector is sparse, only its components need to be visited.
+	if ok2 {
+		v1, v2 = v2, v1
+	}
+	var sum float64
+	v1.ForEachNonZero(func(i int, x float64) {
+		sum += x * v2.At(i)
+	})
+	return sum
+}
+
+func squaredDistance(v1, v2 Vector) float64 {
+	var sum float64
+	sparse1, ok1 := sparseVector(v1)
+	sparse2, ok2 := sparseVector(v2)
+	if ok1 && ok2 {
+		i1, i2 := sparse1.Indices, sparse2.Indices
+		var i, j int
+		for i < len(i1) || j < len(i2) {
+			if j == len(i2) || (i < len(i1) && i1[i] < i2[j]) {
+				sum += sparse1.Values[i] * sparse1.Values[i]
+				i++
+			} else if i == len(i1) || i2[j] < i1[i] {
+				sum += sparse2.Values[j] * sparse2.Values[j]
+				j++
+			} else {
+				sum += math.Pow(sparse1.Values[i]-sparse2.Values[j], 2)
+				i++
+				j++
+			}
+		}
+		return sum
+	} else if !ok1 && !ok2 {
+		if s1, ok := v1.(Sample); ok {
+			if s2, ok := v2.(Sample); ok {
+				for i, x := range s1.V {
+					sum += math.Pow(x-s2.V[i], 2)
+				}
+				return sum
+			}
+		}
+	}
+	return dot(v1, v1) + dot(v2, v2) - 2*dot(v1, v2)
+}
@@ -0,0 +1,205 @@
+package svm
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestVectorKernels(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	kernels := []struct {
+		name   string
+		dense  Kernel
+		vector VectorKernel
+	}{
+		{"linear", LinearKernel, LinearVectorKernel},
+		{"rbf", RadialBasisKernel(0.01), RadialBasisVectorKernel(0.01)},
+	}
+	for i := 0; i < 10; i++ {
+		dense1, sparse1 := randomSparseSample(rng, 100, 0.2)
+		dense2, sparse2 := randomSparseSample(rng, 100, 0.2)
+		for _, k := range kernels {
+			expected := k.dense(dense1, dense2)
+			for _, pair := range [][2]Vector{
+				{sparse1, sparse2},
+				{sparse1, dense2},
+				{dense1, sparse2},
+				{dense1, dense2},
+			} {
+				actual := k.vector(pair[0], pair[1])
+				if math.Abs(actual-expected) > 1e-9*math.Max(1, math.Abs(expected)) {
+					t.Errorf("%s: expected %f but got %f for %T and %T", k.name, expected,
+						actual, pair[0], pair[1])
+				}
+			}
+		}
+	}
+}
+
+func TestSparseSampleAt(t *testing.T) {
+	sample := SparseSample{Indices: []int{1, 4, 5}, Values: []float64{2, 3, -1}, Length: 7}
+	expected := []float64{0, 2, 0, 0, 3, -1, 0}
+	for i, x := range expected {
+		if actual := sample.At(i); actual != x {
+			t.Errorf("component %d should be %f but got %f", i, x, actual)
+		} else if actual := sample.Sample().At(i); actual != x {
+			t.Errorf("wrapped component %d should be %f but got %f", i, x, actual)
+		}
+	}
+	if !sample.Sample().Equal(Sample{V: expected}, 0) {
+		t.Error("wrapped sample should equal its dense form")
+	}
+}
+
+func TestSparseSampleDense(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		dense1, _ := randomSparseSample(rng, 50, 0.2)
+		dense2, _ := randomSparseSample(rng, 50, 0.2)
+		features := map[int]float64{}
+		for j, x := range dense1.V {
+			features[j] = x
+		}
+		sparse := NewSparseSample(features)
+		if len(sparse.Indices) != len(sparse.Values) || !sort.IntsAreSorted(sparse.Indices) {
+			t.Fatal("invalid indices:", sparse.Indices)
+		}
+		for _, x := range sparse.Values {
+			if x == 0 {
+				t.Fatal("zero values should not be stored")
+			}
+		}
+
+		converted, err := sparse.Dense(50)
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(converted.V, dense1.V) {
+			t.Fatal("round trip changed the sample")
+		}
+
+		sparse.Length = 50
+		for _, k := range []VectorKernel{LinearVectorKernel, RadialBasisVectorKernel(0.1)} {
+			expected := k(dense1, dense2)
+			if actual := k(sparse, dense2); math.Abs(actual-expected) > 1e-9 {
+				t.Errorf("kernel should be %f but got %f", expected, actual)
+			}
+		}
+	}
+
+	sparse := NewSparseSample(map[int]float64{2: 1, 7: -3, 4: 0})
+	if sparse.Length != 8 || !reflect.DeepEqual(sparse.Indices, []int{2, 7}) {
+		t.Errorf("unexpected sample: %+v", sparse)
+	}
+	if _, err := sparse.Dense(7); err == nil {
+		t.Error("expected error for out-of-bounds index")
+	}
+	if empty := NewSparseSample(nil); empty.Length != 0 || len(empty.Indices) != 0 {
+		t.Errorf("unexpected empty sample: %+v", empty)
+	}
+}
+
+func TestVectorKernelAdapter(t *testing.T) {
+	s1 := Sample{V: []float64{1, 2, 3}}
+	s2 := Sample{V: []float64{-1, 0, 2}}
+	if actual := VectorKernel(LinearVectorKernel).Kernel()(s1, s2); actual != 5 {
+		t.Error("expected 5 but got", actual)
+	}
+}
+
+func TestSparseProblem(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	normal, _ := randomSparseSample(rng, 200, 0.05)
+	dense := &Problem{Kernel: LinearKernel}
+	sparse := &Problem{Kernel: LinearKernel}
+	var testDense, testSparse []Sample
+	var testLabels []bool
+	for len(testDense) < 40 {
+		d, s := randomSparseSample(rng, 200, 0.1)
+		d.UserInfo = dense.sampleCount() + len(testDense) + 1
+		s.UserInfo = d.UserInfo
+		product := LinearKernel(normal, d)
+		if math.Abs(product) < 0.5 {
+			continue
+		}
+		if dense.sampleCount() == 100 {
+			testDense = append(testDense, d)
+			testSparse = append(testSparse, s.Sample())
+			testLabels = append(testLabels, product > 0)
+		} else if product > 0 {
+			dense.AddPositive(d)
+			sparse.AddPositive(s.Sample())
+		} else {
+			dense.AddNegative(d)
+			sparse.AddNegative(s.Sample())
+		}
+	}
+	if err := sparse.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	checkClassifiers := func(name string, denseClassifier, sparseClassifier Classifier) {
+		var correct int
+		for i, s := range testSparse {
+			expected := denseClassifier.Rating(testDense[i])
+			actual := sparseClassifier.Rating(s)
+			if math.Abs(actual-expected) > 1e-6*math.Max(1, math.Abs(expected)) {
+				t.Errorf("%s: rating %d should be %f but got %f", name, i, expected, actual)
+			}
+			if sparseClassifier.Classify(s) == testLabels[i] {
+				correct++
+			}
+		}
+		if correct < len(testSparse)*3/4 {
+			t.Errorf("%s: only %d of %d test samples classified correctly", name, correct,
+				len(testSparse))
+		}
+	}
+
+	for _, kernel := range []Kernel{LinearKernel, RadialBasisKernel(0.01)} {
+		dense.Kernel, sparse.Kernel = kernel, kernel
+		solver := NewSMOSolver(0.001)
+		checkClassifiers("smo", solver.Solve(dense), solver.Solve(sparse))
+	}
+	dense.Kernel, sparse.Kernel = LinearKernel, LinearKernel
+	subgradient := &SubgradientSolver{Tradeoff: 0.001, Steps: 200, StepSize: 0.01}
+	checkClassifiers("subgradient", subgradient.Solve(dense), subgradient.Solve(sparse))
+}
+
+func BenchmarkDenseDot(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	dense1, _ := randomSparseSample(rng, 50000, 0.01)
+	dense2, _ := randomSparseSample(rng, 50000, 0.01)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		LinearKernel(dense1, dense2)
+	}
+}
+
+func BenchmarkSparseDot(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	_, sparse1 := randomSparseSample(rng, 50000, 0.01)
+	_, sparse2 := randomSparseSample(rng, 50000, 0.01)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		LinearVectorKernel(sparse1, sparse2)
+	}
+}
+
+// randomSparseSample generates a random sample in which each component is non-zero with the
+// given probability, in both dense and sparse form.
+func randomSparseSample(rng *rand.Rand, dim int, density float64) (Sample, SparseSample) {
+	dense := Sample{V: make([]float64, dim)}
+	sparse := SparseSample{Length: dim}
+	for i := range dense.V {
+		if rng.Float64() < density {
+			x := rng.NormFloat64()
+			dense.V[i] = x
+			sparse.Indices = append(sparse.Indices, i)
+			sparse.Values = append(sparse.Values, x)
+		}
+	}
+	return dense, sparse
+}